@@ -0,0 +1,814 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp/syntax"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FindingKind tells the kind of Finding.
+type FindingKind string
+
+// Kinds of findings reported by Schema.Analyze.
+const (
+	FindingUnsatisfiable FindingKind = "unsatisfiable" // no instance is valid against the schema.
+	FindingOverlap       FindingKind = "overlap"       // some instance is valid against two branches of oneOf.
+	FindingRedundant     FindingKind = "redundant"     // keyword has no effect on validation.
+)
+
+// Finding is a problem reported by Schema.Analyze.
+type Finding struct {
+	Kind     FindingKind `json:"kind"`
+	Location string      `json:"location"` // absolute location of schema or keyword.
+	Message  string      `json:"message"`
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: %s: %s", f.Kind, f.Location, f.Message)
+}
+
+// Analyze statically checks s, and the schemas reachable from s, for:
+//   - schemas against which no instance is valid, for example because of
+//     conflicting "type" across "allOf", "const" not in "enum", or "enum"
+//     values rejected by other keywords
+//   - pairs of "oneOf" branches accepting same instance, which is therefore
+//     rejected by "oneOf"
+//   - keywords which have no effect on validation
+//
+// The analysis is conservative. A schema is reported unsatisfiable only if it
+// is proven, and overlapping branches are reported along with an instance valid
+// against both. Meta-schemas are not analyzed. Findings are sorted by location.
+func (s *Schema) Analyze() []Finding {
+	a := newAnalyzer()
+	visited := map[*Schema]bool{}
+	var walk func(sch *Schema)
+	walk = func(sch *Schema) {
+		if visited[sch] || findDraft(sch.url()) != nil {
+			return
+		}
+		visited[sch] = true
+		a.check(sch)
+		for _, sub := range subschemas(sch) {
+			walk(sub)
+		}
+	}
+	walk(s)
+	sort.SliceStable(a.findings, func(i, j int) bool {
+		return a.findings[i].Location < a.findings[j].Location
+	})
+	return a.findings
+}
+
+// Unsatisfiable tells whether it is proven that no instance is valid against s.
+func (s *Schema) Unsatisfiable() bool {
+	return newAnalyzer().shape(s).types == 0
+}
+
+// subschemas returns the schemas referred by the keywords of s.
+func subschemas(s *Schema) []*Schema {
+	var list []*Schema
+	add := func(schemas ...*Schema) {
+		for _, sch := range schemas {
+			if sch != nil {
+				list = append(list, sch)
+			}
+		}
+	}
+	add(s.Ref, s.RecursiveRef, s.DynamicRef, s.Not, s.If, s.Then, s.Else)
+	add(s.TypeSchemas...)
+	add(s.DisallowSchemas...)
+	add(s.Extends...)
+	add(s.AllOf...)
+	add(s.AnyOf...)
+	add(s.OneOf...)
+	for _, pname := range s.Properties.Keys() {
+		sch, _ := s.Properties.Get(pname)
+		add(sch.(*Schema))
+	}
+	for _, re := range s.patterns {
+		add(s.PatternProperties[re])
+	}
+	if sch, ok := s.AdditionalProperties.(*Schema); ok {
+		add(sch)
+	}
+	for _, pname := range s.Dependencies.Keys() {
+		if sch, ok := s.Dependencies.RawValues()[pname].(*Schema); ok {
+			add(sch)
+		}
+	}
+	for _, pname := range s.DependentSchemas.Keys() {
+		sch, _ := s.DependentSchemas.Get(pname)
+		add(sch.(*Schema))
+	}
+	for _, pname := range s.PropertyDependencies.Keys() {
+		schemas := s.PropertyDependencies.RawValues()[pname].(*OrderedMap)
+		for _, value := range schemas.Keys() {
+			sch, _ := schemas.Get(value)
+			add(sch.(*Schema))
+		}
+	}
+	add(s.PropertyNames, s.UnevaluatedProperties)
+	switch items := s.Items.(type) {
+	case *Schema:
+		add(items)
+	case []*Schema:
+		add(items...)
+	}
+	if sch, ok := s.AdditionalItems.(*Schema); ok {
+		add(sch)
+	}
+	add(s.PrefixItems...)
+	add(s.Items2020, s.Contains, s.UnevaluatedItems)
+	return list
+}
+
+// jsonTypes is set of json types.
+type jsonTypes uint8
+
+const (
+	typeNull jsonTypes = 1 << iota
+	typeBoolean
+	typeInteger
+	typeFraction // number which is not integer
+	typeString
+	typeArray
+	typeObject
+	typeNumber = typeInteger | typeFraction
+	typeAny    = typeNull | typeBoolean | typeNumber | typeString | typeArray | typeObject
+)
+
+func toJSONTypes(types []string) jsonTypes {
+	var t jsonTypes
+	for _, name := range types {
+		switch name {
+		case "null":
+			t |= typeNull
+		case "boolean":
+			t |= typeBoolean
+		case "integer":
+			t |= typeInteger
+		case "number":
+			t |= typeNumber
+		case "string":
+			t |= typeString
+		case "array":
+			t |= typeArray
+		case "object":
+			t |= typeObject
+		case "any":
+			t |= typeAny
+		}
+	}
+	return t
+}
+
+func valueType(v interface{}) jsonTypes {
+	if t := jsonType(v); t != "number" {
+		return toJSONTypes([]string{t})
+	}
+	if matchesType(v, []string{"integer"}) {
+		return typeInteger
+	}
+	return typeFraction
+}
+
+// bound is lower or upper bound of numbers.
+type bound struct {
+	keyword   string // location of keyword.
+	value     *big.Rat
+	exclusive bool
+}
+
+func (b bound) String() string {
+	return fmt.Sprintf("%s %s", b.keyword, b.value.RatString())
+}
+
+// shape describes what the analyzer knows about the instances valid
+// against a schema.
+type shape struct {
+	types   jsonTypes     // types of valid instances.
+	reasons []string      // why the types are excluded.
+	values  []interface{} // valid instances, if "const" or "enum" is used.
+	finite  bool          // whether values is applicable.
+
+	// used to make example instances.
+	min, max   bound // of numbers. value is nil, if not specified.
+	multipleOf []*big.Rat
+	minLength  int
+	pattern    *syntax.Regexp
+	minItems   int
+	items      []*Schema
+	required   []string
+	properties map[string][]*Schema
+}
+
+func (sh *shape) exclude(types jsonTypes, format string, a ...interface{}) {
+	if sh.types&types != 0 {
+		sh.types &^= types
+		sh.reasons = append(sh.reasons, fmt.Sprintf(format, a...))
+	}
+}
+
+type analyzer struct {
+	shapes   map[*Schema]*shape
+	findings []Finding
+}
+
+func newAnalyzer() *analyzer {
+	return &analyzer{shapes: map[*Schema]*shape{}}
+}
+
+func (a *analyzer) report(kind FindingKind, loc string, format string, args ...interface{}) {
+	a.findings = append(a.findings, Finding{kind, loc, fmt.Sprintf(format, args...)})
+}
+
+// conjunct is a schema which applies to same instance as the schema
+// being analyzed, and must be valid.
+type conjunct struct {
+	path   string // relative-json-pointer from schema being analyzed.
+	schema *Schema
+}
+
+// conjuncts returns s and the schemas it applies through "$ref", "allOf"
+// and "extends".
+func conjuncts(list []conjunct, c conjunct) []conjunct {
+	for _, item := range list {
+		if item.schema == c.schema {
+			return list
+		}
+	}
+	list = append(list, c)
+	s := c.schema
+	if s.Ref != nil {
+		list = conjuncts(list, conjunct{joinPtr(c.path, "$ref"), s.Ref})
+	}
+	for i, sch := range s.AllOf {
+		list = conjuncts(list, conjunct{joinPtr(c.path, "allOf/"+strconv.Itoa(i)), sch})
+	}
+	for i, sch := range s.Extends {
+		list = conjuncts(list, conjunct{joinPtr(c.path, "extends/"+strconv.Itoa(i)), sch})
+	}
+	return list
+}
+
+// shape returns the shape of s. The schemas which are being analyzed,
+// due to recursion, are assumed to accept any instance.
+func (a *analyzer) shape(s *Schema) *shape {
+	if sh, ok := a.shapes[s]; ok {
+		return sh
+	}
+	a.shapes[s] = &shape{types: typeAny}
+	sh := a.compute(s)
+	a.shapes[s] = sh
+	return sh
+}
+
+func (a *analyzer) compute(s *Schema) *shape {
+	sh := &shape{types: typeAny, properties: map[string][]*Schema{}}
+	list := conjuncts(nil, conjunct{"", s})
+	for _, c := range list[1:] {
+		if a.shape(c.schema).types == 0 {
+			sh.exclude(typeAny, "%s is unsatisfiable", c.path)
+			return sh
+		}
+	}
+
+	minLength, maxLength := bound{}, bound{}
+	minItems, maxItems := bound{}, bound{}
+	minProps, maxProps := bound{}, bound{}
+	lower := func(b *bound, kw string, n int) {
+		if n >= 0 && (b.value == nil || b.value.Cmp(big.NewRat(int64(n), 1)) < 0) {
+			*b = bound{keyword: kw, value: big.NewRat(int64(n), 1)}
+		}
+	}
+	upper := func(b *bound, kw string, n int) {
+		if n >= 0 && (b.value == nil || b.value.Cmp(big.NewRat(int64(n), 1)) > 0) {
+			*b = bound{keyword: kw, value: big.NewRat(int64(n), 1)}
+		}
+	}
+	var valuesKw string
+	intersect := func(kw string, values []interface{}) {
+		if !sh.finite {
+			sh.values, sh.finite, valuesKw = values, true, kw
+			return
+		}
+		var common []interface{}
+		for _, v := range sh.values {
+			for _, item := range values {
+				if equals(v, item) {
+					common = append(common, v)
+					break
+				}
+			}
+		}
+		if len(common) == 0 {
+			sh.exclude(typeAny, "%s and %s have no common value", valuesKw, kw)
+		}
+		sh.values = common
+	}
+
+	for _, c := range list {
+		cs, kw := c.schema, func(name string) string { return joinPtr(c.path, name) }
+		if cs.Always != nil && !*cs.Always {
+			sh.exclude(typeAny, "schema is false")
+			continue
+		}
+		if len(cs.Types) > 0 || len(cs.TypeSchemas) > 0 {
+			types := toJSONTypes(cs.Types)
+			if cs.Nullable {
+				types |= typeNull
+			}
+			for _, sch := range cs.TypeSchemas {
+				types |= a.shape(sch).types
+			}
+			sh.exclude(typeAny&^types, "%s allows only %s", kw("type"), quoteAll(cs.Types))
+		}
+		if len(cs.Disallow) > 0 {
+			sh.exclude(toJSONTypes(cs.Disallow), "%s disallows %s", kw("disallow"), quoteAll(cs.Disallow))
+		}
+		if cs.Not != nil && isEmptySchema(cs.Not) {
+			sh.exclude(typeAny, "%s rejects every instance", kw("not"))
+		}
+		if _, ok := cs.Data["const"]; !ok && cs.Constant != nil {
+			intersect(kw("const"), cs.Constant)
+		}
+		if _, ok := cs.Data["enum"]; !ok && cs.Enum != nil {
+			intersect(kw("enum"), cs.Enum)
+		}
+		for _, alt := range []struct {
+			name     string
+			branches []*Schema
+		}{{"anyOf", cs.AnyOf}, {"oneOf", cs.OneOf}} {
+			if len(alt.branches) == 0 {
+				continue
+			}
+			var types jsonTypes
+			for _, b := range alt.branches {
+				types |= a.shape(b).types
+			}
+			if types == 0 {
+				sh.exclude(typeAny, "all branches of %s are unsatisfiable", kw(alt.name))
+			} else {
+				sh.exclude(typeAny&^types, "%s allows only %s", kw(alt.name), typeNames(types))
+			}
+		}
+
+		// numbers
+		if _, ok := cs.Data["minimum"]; !ok && cs.Minimum != nil {
+			sh.lower(bound{kw("minimum"), cs.Minimum, false})
+		}
+		if _, ok := cs.Data["exclusiveMinimum"]; !ok && cs.ExclusiveMinimum != nil {
+			sh.lower(bound{kw("exclusiveMinimum"), cs.ExclusiveMinimum, true})
+		}
+		if _, ok := cs.Data["maximum"]; !ok && cs.Maximum != nil {
+			sh.upper(bound{kw("maximum"), cs.Maximum, false})
+		}
+		if _, ok := cs.Data["exclusiveMaximum"]; !ok && cs.ExclusiveMaximum != nil {
+			sh.upper(bound{kw("exclusiveMaximum"), cs.ExclusiveMaximum, true})
+		}
+		if _, ok := cs.Data["multipleOf"]; !ok && cs.MultipleOf != nil {
+			sh.multipleOf = append(sh.multipleOf, cs.MultipleOf)
+			if cs.MultipleOf.IsInt() {
+				sh.exclude(typeFraction, "%s %s allows only integers", kw("multipleOf"), cs.MultipleOf.RatString())
+			}
+		}
+
+		// strings
+		if _, ok := cs.Data["minLength"]; !ok {
+			lower(&minLength, kw("minLength"), cs.MinLength)
+		}
+		if _, ok := cs.Data["maxLength"]; !ok {
+			upper(&maxLength, kw("maxLength"), cs.MaxLength)
+		}
+		if _, ok := cs.Data["pattern"]; !ok && cs.Pattern != nil && sh.pattern == nil {
+			if re, err := syntax.Parse(cs.Pattern.String(), syntax.Perl); err == nil {
+				sh.pattern = re.Simplify()
+			}
+		}
+
+		// arrays
+		if _, ok := cs.Data["minItems"]; !ok {
+			lower(&minItems, kw("minItems"), cs.MinItems)
+		}
+		if _, ok := cs.Data["maxItems"]; !ok {
+			upper(&maxItems, kw("maxItems"), cs.MaxItems)
+		}
+		if cs.Contains != nil {
+			if cs.MinContains > 0 {
+				lower(&minItems, kw("minContains"), cs.MinContains)
+				if a.shape(cs.Contains).types == 0 {
+					sh.exclude(typeArray, "%s is unsatisfiable", kw("contains"))
+				}
+			}
+			if cs.MaxContains >= 0 && cs.MinContains > cs.MaxContains {
+				sh.exclude(typeArray, "%s %d is greater than %s %d", kw("minContains"), cs.MinContains, kw("maxContains"), cs.MaxContains)
+			}
+		}
+		switch items := cs.Items.(type) {
+		case *Schema:
+			sh.items = append(sh.items, items)
+		case []*Schema:
+			if len(items) > 0 {
+				sh.items = append(sh.items, items[0])
+			}
+		}
+		if len(cs.PrefixItems) > 0 {
+			sh.items = append(sh.items, cs.PrefixItems[0])
+		} else if cs.Items2020 != nil {
+			sh.items = append(sh.items, cs.Items2020)
+		}
+
+		// objects
+		if _, ok := cs.Data["minProperties"]; !ok {
+			lower(&minProps, kw("minProperties"), cs.MinProperties)
+		}
+		if _, ok := cs.Data["maxProperties"]; !ok {
+			upper(&maxProps, kw("maxProperties"), cs.MaxProperties)
+		}
+		for _, pname := range cs.Properties.Keys() {
+			sch, _ := cs.Properties.Get(pname)
+			sh.properties[pname] = append(sh.properties[pname], sch.(*Schema))
+		}
+		for _, pname := range cs.Required {
+			if !contains(sh.required, pname) {
+				sh.required = append(sh.required, pname)
+			}
+			if sch, ok := cs.Properties.RawValues()[pname]; ok && a.shape(sch.(*Schema)).types == 0 {
+				sh.exclude(typeObject, "required property %s is unsatisfiable at %s", quote(pname), kw("properties/"+escape(pname)))
+			} else if !ok && cs.AdditionalProperties == false && !matchesPattern(cs, pname) {
+				sh.exclude(typeObject, "required property %s is not allowed by %s", quote(pname), kw("additionalProperties"))
+			}
+		}
+		lower(&minProps, kw("required"), len(cs.Required))
+	}
+
+	// check bounds
+	empty := func(min, max bound) bool {
+		if min.value == nil || max.value == nil {
+			return false
+		}
+		c := min.value.Cmp(max.value)
+		return c > 0 || (c == 0 && (min.exclusive || max.exclusive))
+	}
+	if empty(sh.min, sh.max) {
+		sh.exclude(typeNumber, "no number satisfies both %s and %s", sh.min, sh.max)
+	} else if sh.max.value != nil {
+		if n := sh.minInteger(); n != nil && !sh.below(n) {
+			sh.exclude(typeInteger, "no integer satisfies both %s and %s", sh.min, sh.max)
+		}
+		if sh.min.value != nil && sh.min.value.Cmp(sh.max.value) == 0 && sh.min.value.IsInt() {
+			sh.exclude(typeFraction, "%s and %s allow only integer", sh.min, sh.max)
+		}
+	}
+	for _, m := range sh.multipleOf {
+		if n := sh.minMultiple(m); n != nil && !sh.below(n) {
+			sh.exclude(typeNumber, "no multiple of %s satisfies both %s and %s", m.RatString(), sh.min, sh.max)
+		}
+	}
+	if empty(minLength, maxLength) {
+		sh.exclude(typeString, "%s is greater than %s", minLength, maxLength)
+	}
+	if empty(minItems, maxItems) {
+		sh.exclude(typeArray, "%s is greater than %s", minItems, maxItems)
+	}
+	if empty(minProps, maxProps) {
+		sh.exclude(typeObject, "%s is greater than %s", minProps, maxProps)
+	}
+	if minLength.value != nil {
+		sh.minLength = int(minLength.value.Num().Int64())
+	}
+	if minItems.value != nil {
+		sh.minItems = int(minItems.value.Num().Int64())
+	}
+
+	// check values
+	if sh.finite && sh.types != 0 {
+		var valid []interface{}
+		for _, v := range sh.values {
+			if valueType(v)&sh.types == 0 {
+				continue
+			}
+			if _, ok := s.Validate(v).(*ValidationError); !ok {
+				valid = append(valid, v)
+			}
+		}
+		if len(valid) == 0 {
+			if len(sh.values) == 1 {
+				sh.exclude(typeAny, "value of %s is not valid", valuesKw)
+			} else {
+				sh.exclude(typeAny, "none of the values of %s is valid", valuesKw)
+			}
+		}
+		sh.values = valid
+		var types jsonTypes
+		for _, v := range valid {
+			types |= valueType(v)
+		}
+		sh.types &= types
+	}
+	return sh
+}
+
+func matchesPattern(s *Schema, pname string) bool {
+	for _, re := range s.patterns {
+		if re.MatchString(pname) {
+			return true
+		}
+	}
+	return false
+}
+
+// isEmptySchema tells whether s accepts every instance, because it has no keywords.
+func isEmptySchema(s *Schema) bool {
+	if s.Always != nil {
+		return *s.Always
+	}
+	m, ok := s.toJSON(NewOrderedMap()).(*OrderedMap)
+	return ok && len(m.Keys()) == 0 && len(s.Extensions) == 0
+}
+
+func (sh *shape) lower(b bound) {
+	if sh.min.value == nil {
+		sh.min = b
+	} else if c := b.value.Cmp(sh.min.value); c > 0 || (c == 0 && b.exclusive) {
+		sh.min = b
+	}
+}
+
+func (sh *shape) upper(b bound) {
+	if sh.max.value == nil {
+		sh.max = b
+	} else if c := b.value.Cmp(sh.max.value); c < 0 || (c == 0 && b.exclusive) {
+		sh.max = b
+	}
+}
+
+// above tells whether n satisfies the lower bound of numbers.
+func (sh *shape) above(n *big.Rat) bool {
+	if sh.min.value == nil {
+		return true
+	}
+	c := n.Cmp(sh.min.value)
+	return c > 0 || (c == 0 && !sh.min.exclusive)
+}
+
+// below tells whether n satisfies the upper bound of numbers.
+func (sh *shape) below(n *big.Rat) bool {
+	if sh.max.value == nil {
+		return true
+	}
+	c := n.Cmp(sh.max.value)
+	return c < 0 || (c == 0 && !sh.max.exclusive)
+}
+
+// minInteger returns the least integer satisfying lower bound of numbers.
+// returns nil, if there is no lower bound.
+func (sh *shape) minInteger() *big.Rat {
+	return sh.minMultiple(big.NewRat(1, 1))
+}
+
+// minMultiple returns the least multiple of m satisfying lower bound of numbers.
+// returns nil, if there is no lower bound.
+func (sh *shape) minMultiple(m *big.Rat) *big.Rat {
+	if sh.min.value == nil {
+		return nil
+	}
+	q := new(big.Rat).Quo(sh.min.value, m)
+	k := new(big.Int).Div(q.Num(), q.Denom()) // floor
+	n := new(big.Rat).Mul(new(big.Rat).SetInt(k), m)
+	for !sh.above(n) {
+		n.Add(n, m)
+	}
+	return n
+}
+
+func typeNames(types jsonTypes) string {
+	var names []string
+	for _, t := range []struct {
+		types jsonTypes
+		name  string
+	}{
+		{typeNull, "null"}, {typeBoolean, "boolean"}, {typeNumber, "number"}, {typeInteger, "integer"},
+		{typeString, "string"}, {typeArray, "array"}, {typeObject, "object"},
+	} {
+		if types&t.types == t.types {
+			names = append(names, t.name)
+			types &^= t.types
+		}
+	}
+	return quoteAll(names)
+}
+
+// examples returns the instances which are likely to be valid against s.
+// depth limits the nesting of arrays and objects.
+func (a *analyzer) examples(s *Schema, depth int) []interface{} {
+	sh := a.shape(s)
+	if sh.finite {
+		return sh.values
+	}
+	var list []interface{}
+	if sh.types&typeNull != 0 {
+		list = append(list, nil)
+	}
+	if sh.types&typeBoolean != 0 {
+		list = append(list, true, false)
+	}
+	if sh.types&typeNumber != 0 {
+		nums := []*big.Rat{big.NewRat(0, 1), big.NewRat(1, 1), big.NewRat(-1, 1), big.NewRat(1, 2)}
+		if n := sh.minInteger(); n != nil {
+			nums = append(nums, n, new(big.Rat).Add(n, big.NewRat(1, 1)))
+		}
+		for _, m := range sh.multipleOf {
+			if n := sh.minMultiple(m); n != nil {
+				nums = append(nums, n)
+			}
+			nums = append(nums, m)
+		}
+		if sh.min.value != nil {
+			nums = append(nums, sh.min.value, new(big.Rat).Add(sh.min.value, big.NewRat(1, 2)))
+		}
+		if sh.max.value != nil {
+			nums = append(nums, sh.max.value, new(big.Rat).Sub(sh.max.value, big.NewRat(1, 2)))
+			max := new(big.Int).Div(sh.max.value.Num(), sh.max.value.Denom())
+			nums = append(nums, new(big.Rat).SetInt(max), new(big.Rat).SetInt(max.Sub(max, big.NewInt(1))))
+		}
+		if sh.min.value != nil && sh.max.value != nil {
+			mid := new(big.Rat).Add(sh.min.value, sh.max.value)
+			nums = append(nums, mid.Quo(mid, big.NewRat(2, 1)))
+		}
+		for _, n := range nums {
+			if n.IsInt() {
+				list = append(list, json.Number(n.RatString()))
+			} else {
+				list = append(list, json.Number(n.FloatString(10)))
+			}
+		}
+	}
+	if sh.types&typeString != 0 {
+		list = append(list, "", strings.Repeat("a", sh.minLength))
+		if sh.pattern != nil {
+			list = append(list, regexExample(sh.pattern))
+		}
+		for _, c := range conjuncts(nil, conjunct{"", s}) {
+			if example, ok := formatExamples[c.schema.Format]; ok {
+				list = append(list, example)
+			}
+		}
+	}
+	if sh.types&typeArray != 0 {
+		list = append(list, []interface{}{})
+		if sh.minItems > 0 && depth > 0 {
+			item, _ := a.validExample(sh.items, depth-1)
+			arr := make([]interface{}, sh.minItems)
+			for i := range arr {
+				arr[i] = item
+			}
+			list = append(list, arr)
+		}
+	}
+	if sh.types&typeObject != 0 {
+		list = append(list, map[string]interface{}{})
+		if len(sh.required) > 0 && depth > 0 {
+			obj := make(map[string]interface{}, len(sh.required))
+			for _, pname := range sh.required {
+				obj[pname], _ = a.validExample(sh.properties[pname], depth-1)
+			}
+			list = append(list, obj)
+		}
+	}
+	return list
+}
+
+// validExample returns an example valid against all of schemas.
+func (a *analyzer) validExample(schemas []*Schema, depth int) (interface{}, bool) {
+	if len(schemas) == 0 {
+		return nil, true
+	}
+	var examples []interface{}
+	for _, sch := range schemas {
+		examples = append(examples, a.examples(sch, depth)...)
+	}
+loop:
+	for _, v := range examples {
+		for _, sch := range schemas {
+			if sch.Validate(v) != nil {
+				continue loop
+			}
+		}
+		return v, true
+	}
+	return nil, false
+}
+
+// formatExamples has a valid string for some of the Formats.
+var formatExamples = map[string]string{
+	"date-time": "2000-01-01T00:00:00Z",
+	"date":      "2000-01-01",
+	"time":      "00:00:00Z",
+	"duration":  "P1D",
+	"email":     "user@example.com",
+	"hostname":  "example.com",
+	"ipv4":      "127.0.0.1",
+	"ipv6":      "::1",
+	"uri":       "http://example.com",
+	"uuid":      "00000000-0000-0000-0000-000000000000",
+}
+
+// check reports the findings of s.
+func (a *analyzer) check(s *Schema) {
+	if s.Always != nil {
+		// false schema is unsatisfiable by intention
+		return
+	}
+	if sh := a.shape(s); sh.types == 0 {
+		a.report(FindingUnsatisfiable, s.Location, "no instance is valid: %s", strings.Join(sh.reasons, "; "))
+		return
+	}
+	a.checkOneOf(s)
+	a.checkRedundant(s)
+}
+
+func (a *analyzer) checkOneOf(s *Schema) {
+	for i, bi := range s.OneOf {
+		if a.shape(bi).types == 0 {
+			continue
+		}
+		for j := i + 1; j < len(s.OneOf); j++ {
+			bj := s.OneOf[j]
+			if a.shape(bj).types == 0 {
+				continue
+			}
+			if v, ok := a.validExample([]*Schema{bi, bj}, 2); ok {
+				b, _ := json.Marshal(v)
+				a.report(FindingOverlap, s.Location+"/oneOf", "oneOf/%d and oneOf/%d both accept %s", i, j, b)
+			}
+		}
+	}
+}
+
+func (a *analyzer) checkRedundant(s *Schema) {
+	kw := func(name string) string { return s.Location + "/" + name }
+	if s.Minimum != nil && s.ExclusiveMinimum != nil {
+		if s.Minimum.Cmp(s.ExclusiveMinimum) <= 0 {
+			a.report(FindingRedundant, kw("minimum"), "minimum %s is implied by exclusiveMinimum %s", s.Minimum.RatString(), s.ExclusiveMinimum.RatString())
+		} else {
+			a.report(FindingRedundant, kw("exclusiveMinimum"), "exclusiveMinimum %s is implied by minimum %s", s.ExclusiveMinimum.RatString(), s.Minimum.RatString())
+		}
+	}
+	if s.Maximum != nil && s.ExclusiveMaximum != nil {
+		if s.Maximum.Cmp(s.ExclusiveMaximum) >= 0 {
+			a.report(FindingRedundant, kw("maximum"), "maximum %s is implied by exclusiveMaximum %s", s.Maximum.RatString(), s.ExclusiveMaximum.RatString())
+		} else {
+			a.report(FindingRedundant, kw("exclusiveMaximum"), "exclusiveMaximum %s is implied by maximum %s", s.ExclusiveMaximum.RatString(), s.Maximum.RatString())
+		}
+	}
+	for _, min := range []struct {
+		name  string
+		value int
+	}{{"minLength", s.MinLength}, {"minItems", s.MinItems}, {"minProperties", s.MinProperties}} {
+		if _, ok := s.Data[min.name]; !ok && min.value == 0 {
+			a.report(FindingRedundant, kw(min.name), "%s 0 is always satisfied", min.name)
+		}
+	}
+	if s.UniqueItems && s.MaxItems >= 0 && s.MaxItems <= 1 {
+		a.report(FindingRedundant, kw("uniqueItems"), "uniqueItems is always satisfied with maxItems %d", s.MaxItems)
+	}
+	if s.MultipleOf != nil && s.MultipleOf.Cmp(big.NewRat(1, 1)) == 0 && len(s.Types) == 1 && s.Types[0] == "integer" {
+		a.report(FindingRedundant, kw("multipleOf"), "multipleOf 1 is implied by type 'integer'")
+	}
+	if s.Constant != nil && s.Enum != nil {
+		for _, v := range s.Enum {
+			if equals(v, s.Constant[0]) {
+				a.report(FindingRedundant, kw("enum"), "enum is implied by const")
+				break
+			}
+		}
+	}
+	// since draft2019, additionalProperties marks properties evaluated for "unevaluatedProperties"
+	if s.AdditionalProperties == true && s.draft != nil && s.draft.version < 2019 {
+		a.report(FindingRedundant, kw("additionalProperties"), "additionalProperties true is always satisfied")
+	}
+
+	// keywords not applicable to type
+	if len(s.Types) == 0 || len(s.TypeSchemas) > 0 || contains(s.Types, "any") {
+		return
+	}
+	m, ok := s.toJSON(NewOrderedMap()).(*OrderedMap)
+	if !ok {
+		return
+	}
+	types := toJSONTypes(s.Types)
+	for _, name := range m.Keys() {
+		want, ok := typeKeywords[name]
+		if !ok || (name == "required" && s.draft != nil && s.draft.version < 4) {
+			continue
+		}
+		if toJSONTypes(want)&types == 0 {
+			a.report(FindingRedundant, kw(name), "%s is not applicable to type %s", name, quoteAll(s.Types))
+		}
+	}
+}
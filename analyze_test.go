@@ -0,0 +1,140 @@
+package jsonschema_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+func TestSchema_Analyze(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		want   []string
+	}{
+		{
+			name:   "conflicting types",
+			schema: `{"allOf": [{"type": "string"}, {"type": ["number", "null"]}]}`,
+			want: []string{
+				"unsatisfiable: schema.json#: no instance is valid: allOf/0/type allows only 'string'; allOf/1/type allows only 'number', 'null'",
+			},
+		},
+		{
+			name:   "const not in enum",
+			schema: `{"const": "a", "enum": ["b", "c"]}`,
+			want: []string{
+				"unsatisfiable: schema.json#: no instance is valid: const and enum have no common value",
+			},
+		},
+		{
+			name:   "empty enum after intersection",
+			schema: `{"enum": [1, 2, 3], "allOf": [{"enum": [3, 4]}, {"enum": [1, 2]}]}`,
+			want: []string{
+				"unsatisfiable: schema.json#: no instance is valid: enum and allOf/1/enum have no common value",
+			},
+		},
+		{
+			name:   "enum values rejected",
+			schema: `{"enum": [1, "a"], "minimum": 5, "minLength": 2}`,
+			want: []string{
+				"unsatisfiable: schema.json#: no instance is valid: none of the values of enum is valid",
+			},
+		},
+		{
+			name:   "no integer in range",
+			schema: `{"type": "integer", "exclusiveMinimum": 1, "maximum": 1.5}`,
+			want: []string{
+				"unsatisfiable: schema.json#: no instance is valid: type allows only 'integer'; no integer satisfies both exclusiveMinimum 1 and maximum 3/2",
+			},
+		},
+		{
+			name:   "unsatisfiable reference",
+			schema: `{"$defs": {"none": {"not": {}}}, "properties": {"a": {"$ref": "#/$defs/none"}}, "required": ["a"], "type": "object"}`,
+			want: []string{
+				"unsatisfiable: schema.json#: no instance is valid: type allows only 'object'; required property 'a' is unsatisfiable at properties/a",
+				"unsatisfiable: schema.json#/$defs/none: no instance is valid: not rejects every instance",
+				"unsatisfiable: schema.json#/properties/a: no instance is valid: $ref is unsatisfiable",
+			},
+		},
+		{
+			name:   "all branches unsatisfiable",
+			schema: `{"anyOf": [{"type": "string", "maxLength": 1, "minLength": 2}, {"type": "array", "minItems": 2, "maxItems": 1}]}`,
+			want: []string{
+				"unsatisfiable: schema.json#: no instance is valid: all branches of anyOf are unsatisfiable",
+				"unsatisfiable: schema.json#/anyOf/0: no instance is valid: type allows only 'string'; minLength 2 is greater than maxLength 1",
+				"unsatisfiable: schema.json#/anyOf/1: no instance is valid: type allows only 'array'; minItems 2 is greater than maxItems 1",
+			},
+		},
+		{
+			name:   "false schema",
+			schema: `{"properties": {"a": false}, "additionalProperties": false}`,
+		},
+		{
+			name: "overlapping oneOf",
+			schema: `{"oneOf": [
+				{"type": "string"},
+				{"type": "string", "maxLength": 3},
+				{"type": "integer", "minimum": 10},
+				{"enum": [20, null]},
+				{"type": "object", "required": ["kind"], "properties": {"kind": {"const": "a"}}},
+				{"type": "object", "required": ["kind"], "properties": {"kind": {"const": "b"}}}
+			]}`,
+			want: []string{
+				`overlap: schema.json#/oneOf: oneOf/0 and oneOf/1 both accept ""`,
+				`overlap: schema.json#/oneOf: oneOf/2 and oneOf/3 both accept 20`,
+			},
+		},
+		{
+			name:   "redundant keywords",
+			schema: `{"type": "string", "minLength": 0, "maximum": 5, "const": "a", "enum": ["a", "b"]}`,
+			want: []string{
+				"redundant: schema.json#/enum: enum is implied by const",
+				"redundant: schema.json#/maximum: maximum is not applicable to type 'string'",
+				"redundant: schema.json#/minLength: minLength 0 is always satisfied",
+			},
+		},
+		{
+			name:   "additionalProperties true in draft7",
+			schema: `{"$schema": "http://json-schema.org/draft-07/schema#", "additionalProperties": true}`,
+			want: []string{
+				"redundant: schema.json#/additionalProperties: additionalProperties true is always satisfied",
+			},
+		},
+		{
+			name:   "additionalProperties true with unevaluatedProperties",
+			schema: `{"allOf": [{"additionalProperties": true}], "unevaluatedProperties": false}`,
+		},
+		{
+			name:   "redundant bounds",
+			schema: `{"minimum": 1, "exclusiveMinimum": 1, "maximum": 10, "exclusiveMaximum": 20, "maxItems": 1, "uniqueItems": true}`,
+			want: []string{
+				"redundant: schema.json#/exclusiveMaximum: exclusiveMaximum 20 is implied by maximum 10",
+				"redundant: schema.json#/minimum: minimum 1 is implied by exclusiveMinimum 1",
+				"redundant: schema.json#/uniqueItems: uniqueItems is always satisfied with maxItems 1",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := jsonschema.NewCompiler()
+			if err := c.AddResource("http://example.com/schema.json", strings.NewReader(test.schema)); err != nil {
+				t.Fatal(err)
+			}
+			sch, err := c.Compile("http://example.com/schema.json")
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, f := range sch.Analyze() {
+				got = append(got, strings.Replace(f.String(), "http://example.com/", "", 1))
+			}
+			if strings.Join(got, "\n") != strings.Join(test.want, "\n") {
+				t.Errorf("got:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(test.want, "\n"))
+			}
+			if got, want := sch.Unsatisfiable(), len(test.want) > 0 && strings.HasPrefix(test.want[0], "unsatisfiable: schema.json#:"); got != want {
+				t.Errorf("Unsatisfiable: got %v, want %v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,312 @@
+package jsonschema
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parsePtr splits given json-pointer into its reference tokens.
+func parsePtr(ptr string) ([]string, error) {
+	if ptr == "" {
+		return nil, nil
+	}
+	if ptr[0] != '/' {
+		return nil, fmt.Errorf("jsonschema: invalid json-pointer %s", quote(ptr))
+	}
+	tokens := strings.Split(ptr[1:], "/")
+	for i, tok := range tokens {
+		tok = strings.Replace(tok, "~1", "/", -1)
+		tokens[i] = strings.Replace(tok, "~0", "~", -1)
+	}
+	return tokens, nil
+}
+
+// parseKeywordPtr is like parsePtr, but the reference tokens may also be
+// percent-encoded as in ValidationError.KeywordLocation.
+func parseKeywordPtr(ptr string) ([]string, error) {
+	if ptr == "" {
+		return nil, nil
+	}
+	if ptr[0] != '/' {
+		return nil, fmt.Errorf("jsonschema: invalid json-pointer %s", quote(ptr))
+	}
+	tokens := strings.Split(ptr[1:], "/")
+	for i, tok := range tokens {
+		tokens[i] = unescape(tok)
+	}
+	return tokens, nil
+}
+
+// instanceLocation returns the instance location for given reference tokens.
+func instanceLocation(tokens []string) string {
+	var loc string
+	for _, tok := range tokens {
+		loc += "/" + escape(tok)
+	}
+	return loc
+}
+
+// child returns the value for given reference token in v.
+func child(v interface{}, tok string) (interface{}, bool) {
+	if om, ok := v.(*OrderedMap); ok {
+		v = om.RawValues()
+	}
+	switch v := v.(type) {
+	case map[string]interface{}:
+		cv, ok := v[tok]
+		return cv, ok
+	case []interface{}:
+		if i, err := strconv.Atoi(tok); err == nil && i >= 0 && i < len(v) && strconv.Itoa(i) == tok {
+			return v[i], true
+		}
+	}
+	return nil, false
+}
+
+// inplace appends ref, and the subschemas that apply to the same instance
+// location through "$ref" and "allOf", to refs.
+func inplace(refs []schemaRef, ref schemaRef) []schemaRef {
+	for _, r := range refs {
+		if r.schema == ref.schema {
+			return refs
+		}
+	}
+	refs = append(refs, ref)
+	s := ref.schema
+	if s.Ref != nil {
+		refs = inplace(refs, schemaRef{joinPtr(ref.path, "$ref"), s.Ref, false})
+	}
+	for i, sch := range s.AllOf {
+		refs = inplace(refs, schemaRef{joinPtr(ref.path, "allOf/"+strconv.Itoa(i)), sch, false})
+	}
+	return refs
+}
+
+// children returns the subschemas of ref, that apply to the value of
+// property or item tok in v.
+func children(ref schemaRef, v interface{}, tok string) []schemaRef {
+	var refs []schemaRef
+	add := func(path string, sch *Schema) {
+		refs = append(refs, schemaRef{joinPtr(ref.path, path), sch, false})
+	}
+	s := ref.schema
+	if om, ok := v.(*OrderedMap); ok {
+		v = om.RawValues()
+	}
+	switch v.(type) {
+	case map[string]interface{}:
+		matched := false
+		if sch, ok := s.Properties.RawValues()[tok]; ok {
+			add("properties/"+escape(tok), sch.(*Schema))
+			matched = true
+		}
+		for _, re := range s.patterns {
+			if re.MatchString(tok) {
+				add("patternProperties/"+escape(re.String()), s.PatternProperties[re])
+				matched = true
+			}
+		}
+		if sch, ok := s.AdditionalProperties.(*Schema); ok && !matched {
+			add("additionalProperties", sch)
+		}
+	case []interface{}:
+		i, _ := strconv.Atoi(tok)
+		switch items := s.Items.(type) {
+		case *Schema:
+			add("items", items)
+		case []*Schema:
+			if i < len(items) {
+				add("items/"+tok, items[i])
+			} else if sch, ok := s.AdditionalItems.(*Schema); ok {
+				add("additionalItems", sch)
+			}
+		}
+		if i < len(s.PrefixItems) {
+			add("prefixItems/"+tok, s.PrefixItems[i])
+		} else if s.Items2020 != nil {
+			add("items", s.Items2020)
+		}
+	}
+	return refs
+}
+
+// At returns the subschema of s at given keyword path. For example
+// "/properties/address/items" returns the schema of items in address property.
+// The tokens of ptr may be percent-encoded as in ValidationError.KeywordLocation.
+//
+// Only the keywords which hold subschemas are supported. Since "$defs" and
+// "definitions" are not part of compiled schema, they cannot be navigated.
+func (s *Schema) At(ptr string) (*Schema, error) {
+	tokens, err := parseKeywordPtr(ptr)
+	if err != nil {
+		return nil, err
+	}
+	sch := s
+	for len(tokens) > 0 {
+		n, next := 1, (*Schema)(nil)
+		arg := func(i int) (string, bool) {
+			if i >= len(tokens) {
+				return "", false
+			}
+			n = i + 1
+			return tokens[i], true
+		}
+		index := func(arr []*Schema) *Schema {
+			if tok, ok := arg(1); ok {
+				if i, err := strconv.Atoi(tok); err == nil && i >= 0 && i < len(arr) {
+					return arr[i]
+				}
+			}
+			return nil
+		}
+		prop := func(m *OrderedMap) *Schema {
+			if tok, ok := arg(1); ok && m != nil {
+				next, _ := m.RawValues()[tok].(*Schema)
+				return next
+			}
+			return nil
+		}
+		switch tokens[0] {
+		case "$ref":
+			next = sch.Ref
+		case "$recursiveRef":
+			next = sch.RecursiveRef
+		case "$dynamicRef":
+			next = sch.DynamicRef
+		case "not":
+			next = sch.Not
+		case "if":
+			next = sch.If
+		case "then":
+			next = sch.Then
+		case "else":
+			next = sch.Else
+		case "extends":
+			next = index(sch.Extends)
+		case "allOf":
+			next = index(sch.AllOf)
+		case "anyOf":
+			next = index(sch.AnyOf)
+		case "oneOf":
+			next = index(sch.OneOf)
+		case "properties":
+			next = prop(sch.Properties)
+		case "patternProperties":
+			if tok, ok := arg(1); ok {
+				for _, re := range sch.patterns {
+					if re.String() == tok {
+						next = sch.PatternProperties[re]
+					}
+				}
+			}
+		case "additionalProperties":
+			next, _ = sch.AdditionalProperties.(*Schema)
+		case "propertyNames":
+			next = sch.PropertyNames
+		case "dependencies":
+			next = prop(sch.Dependencies)
+		case "dependentSchemas":
+			next = prop(sch.DependentSchemas)
+		case "propertyDependencies":
+			if pname, ok := arg(1); ok && sch.PropertyDependencies != nil {
+				m, _ := sch.PropertyDependencies.RawValues()[pname].(*OrderedMap)
+				if value, ok := arg(2); ok {
+					next, _ = m.RawValues()[value].(*Schema)
+				}
+			}
+		case "unevaluatedProperties":
+			next = sch.UnevaluatedProperties
+		case "items":
+			switch items := sch.Items.(type) {
+			case *Schema:
+				next = items
+			case []*Schema:
+				next = index(items)
+			default:
+				next = sch.Items2020
+			}
+		case "additionalItems":
+			next, _ = sch.AdditionalItems.(*Schema)
+		case "prefixItems":
+			next = index(sch.PrefixItems)
+		case "contains":
+			next = sch.Contains
+		case "unevaluatedItems":
+			next = sch.UnevaluatedItems
+		}
+		if next == nil {
+			return nil, fmt.Errorf("jsonschema: no subschema at %s in %s", quote(ptr), sch.Location)
+		}
+		sch, tokens = next, tokens[n:]
+	}
+	return sch, nil
+}
+
+// ValidateAt validates the value at json-pointer ptr in doc, against the
+// subschemas of s that apply to it. Subschemas are found through "properties",
+// "patternProperties", "additionalProperties", "items", "prefixItems",
+// "additionalItems", "$ref" and "allOf". Other keywords such as "anyOf",
+// "oneOf" and "if", and keywords of ancestors such as "required" are not
+// checked.
+//
+// Keyword locations in returned *ValidationError are relative to s, as if
+// doc were validated using s.Validate.
+//
+// returns error if ptr is not found in doc. Otherwise returned errors are
+// same as s.Validate.
+func (s *Schema) ValidateAt(doc interface{}, ptr string) (err error) {
+	tokens, err := parsePtr(ptr)
+	if err != nil {
+		return err
+	}
+	defer recoverValidation(&err)
+	errs, err := s.validateAt(doc, tokens)
+	if err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return s.validationFailed(instanceLocation(tokens), errs...)
+	}
+	return nil
+}
+
+// validateAt validates the value at given reference tokens in doc, against
+// the subschemas of s that apply to it through "properties", "patternProperties",
+// "additionalProperties", "items", "prefixItems", "additionalItems", "$ref" and
+// "allOf". Other keywords such as "anyOf", "oneOf" and "if" are not followed.
+//
+// It returns validation errors, which are not wrapped, and error if tokens
+// are not found in doc.
+func (s *Schema) validateAt(doc interface{}, tokens []string) ([]error, error) {
+	if len(tokens) == 0 {
+		if _, err := s.validate(nil, 0, "", doc, doc, ""); err != nil {
+			return []error{err}, nil
+		}
+		return nil, nil
+	}
+	v, refs := doc, []schemaRef{{"", s, false}}
+	for i, tok := range tokens {
+		cv, ok := child(v, tok)
+		if !ok {
+			return nil, fmt.Errorf("jsonschema: %s not found in instance", quote(instanceLocation(tokens[:i+1])))
+		}
+		var expanded []schemaRef
+		for _, ref := range refs {
+			expanded = inplace(expanded, ref)
+		}
+		refs = nil
+		for _, ref := range expanded {
+			refs = append(refs, children(ref, v, tok)...)
+		}
+		v = cv
+	}
+	var errs []error
+	vloc := instanceLocation(tokens)
+	for _, ref := range refs {
+		if _, err := ref.schema.validate([]schemaRef{{"", s, false}}, 0, ref.path, doc, v, vloc); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs, nil
+}
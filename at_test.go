@@ -0,0 +1,106 @@
+package jsonschema_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+func TestSchema_At(t *testing.T) {
+	sch := jsonschema.MustCompileString("http://example.com/schema.json", `{
+		"properties": {
+			"a/b": {"items": {"$ref": "#/$defs/item"}},
+			"tuple": {"prefixItems": [{"type": "string"}, {"type": "integer"}]},
+			"~1": {"type": "string"},
+			"/": {"type": "integer"}
+		},
+		"patternProperties": {"^x-": {"type": "string"}},
+		"allOf": [{"not": {"type": "null"}}],
+		"$defs": {"item": {"type": "string"}}
+	}`)
+	tests := []struct {
+		ptr  string
+		want string // location of subschema, empty if not found
+	}{
+		{"", "http://example.com/schema.json#"},
+		{"/properties/a~1b/items", "http://example.com/schema.json#/properties/a~1b/items"},
+		{"/properties/a~1b/items/$ref", "http://example.com/schema.json#/$defs/item"},
+		{"/properties/tuple/prefixItems/1", "http://example.com/schema.json#/properties/tuple/prefixItems/1"},
+		{"/properties/~01", "http://example.com/schema.json#/properties/~01"},
+		{"/properties/~1", "http://example.com/schema.json#/properties/~1"},
+		{"/patternProperties/%5Ex-", "http://example.com/schema.json#/patternProperties/%5Ex-"},
+		{"/patternProperties/^x-", "http://example.com/schema.json#/patternProperties/%5Ex-"},
+		{"/allOf/0/not", "http://example.com/schema.json#/allOf/0/not"},
+		{"/properties/c", ""},
+		{"/properties/tuple/prefixItems/2", ""},
+		{"/allOf/x", ""},
+		{"/properties", ""},
+		{"/$defs/item", ""},
+		{"properties", ""},
+	}
+	for _, test := range tests {
+		got, err := sch.At(test.ptr)
+		if test.want == "" {
+			if err == nil {
+				t.Errorf("%q: want error, got %s", test.ptr, got.Location)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: %v", test.ptr, err)
+			continue
+		}
+		if got.Location != test.want {
+			t.Errorf("%q: got %s, want %s", test.ptr, got.Location, test.want)
+		}
+	}
+}
+
+func TestSchema_ValidateAt(t *testing.T) {
+	sch := jsonschema.MustCompileString("http://example.com/schema.json", `{
+		"properties": {
+			"items": {"type": "array", "items": {"$ref": "#/$defs/item"}}
+		},
+		"required": ["name"],
+		"$defs": {
+			"item": {
+				"properties": {
+					"address": {"properties": {"zip": {"type": "string"}}, "required": ["city"]}
+				}
+			}
+		}
+	}`)
+	doc := decodeString(t, `{"items": [{}, {}, {}, {"address": {"zip": 123}}]}`)
+
+	// root is invalid, because of missing name
+	if err := sch.ValidateAt(doc, "/items/0"); err != nil {
+		t.Errorf("/items/0: %v", err)
+	}
+	if err := sch.ValidateAt(doc, "/items/4"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("/items/4: want not found error, got %v", err)
+	}
+	if err := sch.ValidateAt(doc, "items"); err == nil {
+		t.Error("invalid pointer: want error")
+	}
+
+	err := sch.ValidateAt(doc, "/items/3/address")
+	ve, ok := err.(*jsonschema.ValidationError)
+	if !ok {
+		t.Fatalf("want *ValidationError, got %#v", err)
+	}
+	if ve.InstanceLocation != "/items/3/address" {
+		t.Errorf("instance location: got %q", ve.InstanceLocation)
+	}
+	var got []string
+	for _, e := range ve.BasicOutput().Errors[1:] {
+		got = append(got, e.KeywordLocation+" "+e.AbsoluteKeywordLocation+" "+e.InstanceLocation)
+	}
+	want := []string{
+		"/properties/items/items/$ref/properties/address/required http://example.com/schema.json#/$defs/item/properties/address/required /items/3/address",
+		"/properties/items/items/$ref/properties/address/properties/zip/type http://example.com/schema.json#/$defs/item/properties/address/properties/zip/type /items/3/address/zip",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
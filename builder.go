@@ -0,0 +1,614 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// A Builder builds json-schema document programmatically.
+//
+//	b := jsonschema.Object().
+//		Prop("id", jsonschema.String().Format("uuid")).
+//		Prop("tags", jsonschema.Array().Items(jsonschema.String())).
+//		Required("id")
+//
+// Keywords are recorded independent of draft, and are rendered using
+// the draft given to Doc. For example PrefixItems is rendered as "items"
+// array before draft2020-12, and Def is rendered under "definitions"
+// before draft2019-09. Keywords which cannot be expressed in the draft
+// are reported as error by Doc.
+//
+// Builder is mutable. All methods modify the receiver and return it to
+// allow chaining.
+type Builder struct {
+	always   *bool
+	keywords *OrderedMap
+}
+
+func newBuilder(types ...string) *Builder {
+	b := &Builder{keywords: NewOrderedMap()}
+	if len(types) > 0 {
+		b.Type(types...)
+	}
+	return b
+}
+
+// Any returns a Builder for schema without any constraints.
+func Any() *Builder { return newBuilder() }
+
+// True returns a Builder for boolean schema true.
+func True() *Builder {
+	v := true
+	return &Builder{always: &v, keywords: NewOrderedMap()}
+}
+
+// False returns a Builder for boolean schema false.
+func False() *Builder {
+	v := false
+	return &Builder{always: &v, keywords: NewOrderedMap()}
+}
+
+// Object returns a Builder for schema of type object.
+func Object() *Builder { return newBuilder("object") }
+
+// Array returns a Builder for schema of type array.
+func Array() *Builder { return newBuilder("array") }
+
+// String returns a Builder for schema of type string.
+func String() *Builder { return newBuilder("string") }
+
+// Number returns a Builder for schema of type number.
+func Number() *Builder { return newBuilder("number") }
+
+// Integer returns a Builder for schema of type integer.
+func Integer() *Builder { return newBuilder("integer") }
+
+// Boolean returns a Builder for schema of type boolean.
+func Boolean() *Builder { return newBuilder("boolean") }
+
+// Null returns a Builder for schema of type null.
+func Null() *Builder { return newBuilder("null") }
+
+// Ref returns a Builder for schema referring to given uri-reference.
+func Ref(ref string) *Builder { return newBuilder().Ref(ref) }
+
+// set sets keyword value. boolean schemas are promoted to empty schema.
+func (b *Builder) set(kw string, v interface{}) *Builder {
+	if b.always != nil {
+		if !*b.always {
+			b.keywords.Set("not", True())
+		}
+		b.always = nil
+	}
+	b.keywords.Set(kw, v)
+	return b
+}
+
+// setProp sets value of property pname in keyword kw whose value is object.
+func (b *Builder) setProp(kw, pname string, v interface{}) *Builder {
+	m, ok := b.keywords.Get(kw)
+	if !ok {
+		m = NewOrderedMap()
+		b.set(kw, m)
+	}
+	m.(*OrderedMap).Set(pname, v)
+	return b
+}
+
+// appendItems appends values to keyword kw whose value is array.
+func (b *Builder) appendItems(kw string, items ...interface{}) *Builder {
+	var arr []interface{}
+	if v, ok := b.keywords.Get(kw); ok {
+		arr = v.([]interface{})
+	}
+	return b.set(kw, append(arr, items...))
+}
+
+func builders(bb []*Builder) []interface{} {
+	arr := make([]interface{}, len(bb))
+	for i, b := range bb {
+		arr[i] = b
+	}
+	return arr
+}
+
+func number(n float64) json.Number {
+	return json.Number(strconv.FormatFloat(n, 'f', -1, 64))
+}
+
+// Keyword sets the given keyword to v. This is useful for keywords that
+// have no dedicated method, such as keywords introduced by extensions.
+// v may contain *Builder values.
+func (b *Builder) Keyword(name string, v interface{}) *Builder { return b.set(name, v) }
+
+// core ---
+
+// ID sets "$id" ("id" in draft4).
+func (b *Builder) ID(id string) *Builder { return b.set("$id", id) }
+
+// Draft sets "$schema" to the url of given draft.
+func (b *Builder) Draft(d *Draft) *Builder { return b.set("$schema", d.url) }
+
+// Ref sets "$ref". References starting with "#/$defs/" are rendered
+// with "#/definitions/" before draft2019-09.
+func (b *Builder) Ref(ref string) *Builder { return b.set("$ref", ref) }
+
+// Anchor sets "$anchor".
+func (b *Builder) Anchor(name string) *Builder { return b.set("$anchor", name) }
+
+// DynamicAnchor sets "$dynamicAnchor". In draft2019-09 it is
+// rendered as "$recursiveAnchor": true, and is allowed only at resource root.
+// It is not supported before draft2019-09.
+func (b *Builder) DynamicAnchor(name string) *Builder { return b.set("$dynamicAnchor", name) }
+
+// DynamicRef sets "$dynamicRef". In draft2019-09 it is rendered as
+// "$recursiveRef": "#", and ref must refer to the DynamicAnchor of its
+// resource root. It is not supported before draft2019-09.
+func (b *Builder) DynamicRef(ref string) *Builder { return b.set("$dynamicRef", ref) }
+
+// Def adds schema with given name to "$defs" ("definitions" before draft2019-09).
+func (b *Builder) Def(name string, sch *Builder) *Builder { return b.setProp("$defs", name, sch) }
+
+// Comment sets "$comment".
+func (b *Builder) Comment(comment string) *Builder { return b.set("$comment", comment) }
+
+// annotations ---
+
+// Title sets "title".
+func (b *Builder) Title(title string) *Builder { return b.set("title", title) }
+
+// Description sets "description".
+func (b *Builder) Description(description string) *Builder { return b.set("description", description) }
+
+// Default sets "default".
+func (b *Builder) Default(v interface{}) *Builder { return b.set("default", v) }
+
+// Examples appends values to "examples".
+func (b *Builder) Examples(v ...interface{}) *Builder { return b.appendItems("examples", v...) }
+
+// ReadOnly sets "readOnly" to true.
+func (b *Builder) ReadOnly() *Builder { return b.set("readOnly", true) }
+
+// WriteOnly sets "writeOnly" to true.
+func (b *Builder) WriteOnly() *Builder { return b.set("writeOnly", true) }
+
+// Deprecated sets "deprecated" to true.
+func (b *Builder) Deprecated() *Builder { return b.set("deprecated", true) }
+
+// type agnostic ---
+
+// Type sets "type". Calling it without types removes "type".
+func (b *Builder) Type(types ...string) *Builder {
+	if len(types) == 0 {
+		b.keywords.Delete("type")
+		return b
+	}
+	if len(types) == 1 {
+		return b.set("type", types[0])
+	}
+	return b.set("type", stringsToJSON(types))
+}
+
+// Enum sets "enum".
+func (b *Builder) Enum(v ...interface{}) *Builder { return b.set("enum", v) }
+
+// Const sets "const". In draft4 it is rendered as "enum" with single value.
+func (b *Builder) Const(v interface{}) *Builder { return b.set("const", v) }
+
+// Not sets "not". In draft3 it is rendered as "disallow".
+func (b *Builder) Not(sch *Builder) *Builder { return b.set("not", sch) }
+
+// AllOf appends schemas to "allOf" ("extends" in draft3).
+func (b *Builder) AllOf(schemas ...*Builder) *Builder { return b.appendItems("allOf", builders(schemas)...) }
+
+// AnyOf appends schemas to "anyOf".
+func (b *Builder) AnyOf(schemas ...*Builder) *Builder { return b.appendItems("anyOf", builders(schemas)...) }
+
+// OneOf appends schemas to "oneOf".
+func (b *Builder) OneOf(schemas ...*Builder) *Builder { return b.appendItems("oneOf", builders(schemas)...) }
+
+// If sets "if".
+func (b *Builder) If(sch *Builder) *Builder { return b.set("if", sch) }
+
+// Then sets "then".
+func (b *Builder) Then(sch *Builder) *Builder { return b.set("then", sch) }
+
+// Else sets "else".
+func (b *Builder) Else(sch *Builder) *Builder { return b.set("else", sch) }
+
+// object ---
+
+// Prop adds property with given name and schema to "properties".
+func (b *Builder) Prop(name string, sch *Builder) *Builder { return b.setProp("properties", name, sch) }
+
+// PatternProp adds schema for given pattern to "patternProperties".
+func (b *Builder) PatternProp(pattern string, sch *Builder) *Builder {
+	return b.setProp("patternProperties", pattern, sch)
+}
+
+// AdditionalProperties sets "additionalProperties".
+func (b *Builder) AdditionalProperties(sch *Builder) *Builder { return b.set("additionalProperties", sch) }
+
+// PropertyNames sets "propertyNames".
+func (b *Builder) PropertyNames(sch *Builder) *Builder { return b.set("propertyNames", sch) }
+
+// Required appends names to "required". In draft3 it is rendered as
+// "required": true in schemas of the named properties.
+func (b *Builder) Required(names ...string) *Builder {
+	return b.appendItems("required", stringsToJSON(names)...)
+}
+
+// MinProperties sets "minProperties".
+func (b *Builder) MinProperties(n int) *Builder { return b.set("minProperties", number(float64(n))) }
+
+// MaxProperties sets "maxProperties".
+func (b *Builder) MaxProperties(n int) *Builder { return b.set("maxProperties", number(float64(n))) }
+
+// DependentRequired adds an entry to "dependentRequired" ("dependencies" before draft2019-09).
+func (b *Builder) DependentRequired(name string, required ...string) *Builder {
+	return b.setProp("dependentRequired", name, stringsToJSON(required))
+}
+
+// DependentSchema adds an entry to "dependentSchemas" ("dependencies" before draft2019-09).
+func (b *Builder) DependentSchema(name string, sch *Builder) *Builder {
+	return b.setProp("dependentSchemas", name, sch)
+}
+
+// UnevaluatedProperties sets "unevaluatedProperties".
+func (b *Builder) UnevaluatedProperties(sch *Builder) *Builder {
+	return b.set("unevaluatedProperties", sch)
+}
+
+// array ---
+
+// Items sets schema for array items, which are not covered by PrefixItems.
+// It is rendered as "additionalItems" before draft2020-12, if PrefixItems is used.
+func (b *Builder) Items(sch *Builder) *Builder { return b.set("items", sch) }
+
+// PrefixItems appends schemas to "prefixItems" ("items" array before draft2020-12).
+func (b *Builder) PrefixItems(schemas ...*Builder) *Builder {
+	return b.appendItems("prefixItems", builders(schemas)...)
+}
+
+// Contains sets "contains".
+func (b *Builder) Contains(sch *Builder) *Builder { return b.set("contains", sch) }
+
+// MinContains sets "minContains".
+func (b *Builder) MinContains(n int) *Builder { return b.set("minContains", number(float64(n))) }
+
+// MaxContains sets "maxContains".
+func (b *Builder) MaxContains(n int) *Builder { return b.set("maxContains", number(float64(n))) }
+
+// MinItems sets "minItems".
+func (b *Builder) MinItems(n int) *Builder { return b.set("minItems", number(float64(n))) }
+
+// MaxItems sets "maxItems".
+func (b *Builder) MaxItems(n int) *Builder { return b.set("maxItems", number(float64(n))) }
+
+// UniqueItems sets "uniqueItems" to true.
+func (b *Builder) UniqueItems() *Builder { return b.set("uniqueItems", true) }
+
+// UnevaluatedItems sets "unevaluatedItems".
+func (b *Builder) UnevaluatedItems(sch *Builder) *Builder { return b.set("unevaluatedItems", sch) }
+
+// string ---
+
+// MinLength sets "minLength".
+func (b *Builder) MinLength(n int) *Builder { return b.set("minLength", number(float64(n))) }
+
+// MaxLength sets "maxLength".
+func (b *Builder) MaxLength(n int) *Builder { return b.set("maxLength", number(float64(n))) }
+
+// Pattern sets "pattern".
+func (b *Builder) Pattern(pattern string) *Builder { return b.set("pattern", pattern) }
+
+// Format sets "format".
+func (b *Builder) Format(format string) *Builder { return b.set("format", format) }
+
+// ContentEncoding sets "contentEncoding".
+func (b *Builder) ContentEncoding(encoding string) *Builder { return b.set("contentEncoding", encoding) }
+
+// ContentMediaType sets "contentMediaType".
+func (b *Builder) ContentMediaType(mediaType string) *Builder {
+	return b.set("contentMediaType", mediaType)
+}
+
+// number ---
+
+// Minimum sets "minimum".
+func (b *Builder) Minimum(n float64) *Builder { return b.set("minimum", number(n)) }
+
+// Maximum sets "maximum".
+func (b *Builder) Maximum(n float64) *Builder { return b.set("maximum", number(n)) }
+
+// ExclusiveMinimum sets "exclusiveMinimum". In draft4 it is rendered as
+// "minimum" along with "exclusiveMinimum": true.
+func (b *Builder) ExclusiveMinimum(n float64) *Builder { return b.set("exclusiveMinimum", number(n)) }
+
+// ExclusiveMaximum sets "exclusiveMaximum". In draft4 it is rendered as
+// "maximum" along with "exclusiveMaximum": true.
+func (b *Builder) ExclusiveMaximum(n float64) *Builder { return b.set("exclusiveMaximum", number(n)) }
+
+// MultipleOf sets "multipleOf" ("divisibleBy" in draft3).
+func (b *Builder) MultipleOf(n float64) *Builder { return b.set("multipleOf", number(n)) }
+
+// rendering ---
+
+// annotations lists keywords which do not affect validation. They are
+// rendered as is, even if target draft does not define them.
+var annotations = []string{
+	"title", "description", "default", "examples", "readOnly", "writeOnly", "deprecated",
+	"$comment", "contentEncoding", "contentMediaType",
+}
+
+// Doc renders json-schema document for given draft. If "$schema" is
+// set using Builder.Draft, that draft is used instead. The returned value
+// is either bool or *OrderedMap.
+//
+// An error is returned if b uses keywords that cannot be expressed in
+// the draft.
+func (b *Builder) Doc(d *Draft) (interface{}, error) {
+	if v, ok := b.keywords.Get("$schema"); ok {
+		url, ok := v.(string)
+		if !ok {
+			return nil, renderError(d, "$schema", "value must be string")
+		}
+		if sd := findDraft(url); sd != nil {
+			d = sd
+		}
+	}
+	return b.render(d, "", "")
+}
+
+// render renders b for draft d. ptr is json-pointer of b in the document,
+// and anchor is "$dynamicAnchor" of the resource enclosing b.
+func (b *Builder) render(d *Draft, ptr, anchor string) (interface{}, error) {
+	if b.always != nil {
+		if d.boolSchema {
+			return *b.always, nil
+		}
+		m := NewOrderedMap()
+		if !*b.always {
+			if d.version < 4 {
+				m.Set("disallow", []interface{}{NewOrderedMap()})
+			} else {
+				m.Set("not", NewOrderedMap())
+			}
+		}
+		return m, nil
+	}
+
+	if err := b.checkValues(d, ptr); err != nil {
+		return nil, err
+	}
+
+	if ptr == "" || b.has("$id") {
+		anchor = ""
+		if v, ok := b.keywords.Get("$dynamicAnchor"); ok {
+			anchor = v.(string)
+		}
+	}
+
+	m := NewOrderedMap()
+	var deps *OrderedMap
+	for _, kw := range b.keywords.Keys() {
+		v, _ := b.keywords.Get(kw)
+		v, err := renderValue(d, v, joinPtr(ptr, escape(kw)), anchor)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case kw == "$id" && d.id != "$id":
+			m.Set(d.id, v)
+		case kw == "$ref" && d.version < 2019 && strings.HasPrefix(v.(string), "#/$defs/"):
+			m.Set(kw, "#/definitions/"+strings.TrimPrefix(v.(string), "#/$defs/"))
+		case kw == "$defs" && d.version < 2019:
+			m.Set("definitions", v)
+		case kw == "$dynamicAnchor" && d.version < 2020:
+			if d.version < 2019 {
+				return nil, renderError(d, joinPtr(ptr, kw), "$dynamicAnchor is not supported")
+			}
+			if ptr != "" && !b.has("$id") {
+				return nil, renderError(d, joinPtr(ptr, kw), "$dynamicAnchor is supported only at resource root")
+			}
+			m.Set("$recursiveAnchor", true)
+		case kw == "$dynamicRef" && d.version < 2020:
+			if d.version < 2019 {
+				return nil, renderError(d, joinPtr(ptr, kw), "$dynamicRef is not supported")
+			}
+			if anchor == "" || v != "#"+anchor {
+				return nil, renderError(d, joinPtr(ptr, kw), "$dynamicRef must refer to $dynamicAnchor of its resource root")
+			}
+			m.Set("$recursiveRef", "#")
+		case kw == "const" && d.version < 6:
+			m.Set("enum", []interface{}{v})
+		case (kw == "exclusiveMinimum" || kw == "exclusiveMaximum") && d.version < 6:
+			m.Set("m"+strings.TrimPrefix(kw, "exclusiveM"), v)
+			m.Set(kw, true)
+		case (kw == "dependentRequired" || kw == "dependentSchemas") && d.version < 2019:
+			if deps == nil {
+				deps = NewOrderedMap()
+				m.Set("dependencies", deps)
+			}
+			for _, pname := range v.(*OrderedMap).Keys() {
+				pvalue, _ := v.(*OrderedMap).Get(pname)
+				deps.Set(pname, pvalue)
+			}
+		case kw == "prefixItems" && d.version < 2020:
+			m.Set("items", v)
+		case kw == "items" && d.version < 2020 && b.has("prefixItems"):
+			m.Set("additionalItems", v)
+		case kw == "required" && d.version < 4:
+			// rendered in property schemas below
+		case kw == "allOf" && d.version < 4:
+			m.Set("extends", v)
+		case kw == "not" && d.version < 4:
+			m.Set("disallow", []interface{}{v})
+		case kw == "multipleOf" && d.version < 4:
+			m.Set("divisibleBy", v)
+		default:
+			m.Set(kw, v)
+		}
+	}
+
+	// in draft3, "required" is boolean in property schema
+	if v, ok := b.keywords.Get("required"); ok && d.version < 4 {
+		props, ok := m.Get("properties")
+		if !ok {
+			props = NewOrderedMap()
+			m.Set("properties", props)
+		}
+		for _, pname := range v.([]interface{}) {
+			pname := pname.(string)
+			psch, ok := props.(*OrderedMap).Get(pname)
+			if !ok {
+				psch = NewOrderedMap()
+			} else if _, ok := psch.(*OrderedMap); !ok {
+				return nil, renderError(d, joinPtr(ptr, "properties/"+escape(pname)), "required property must have object schema")
+			}
+			if _, ok := psch.(*OrderedMap).Get("$ref"); ok {
+				// keywords next to "$ref" are ignored
+				ref := psch
+				psch = NewOrderedMap()
+				psch.(*OrderedMap).Set("extends", ref)
+			}
+			psch.(*OrderedMap).Set("required", true)
+			props.(*OrderedMap).Set(pname, psch)
+		}
+	}
+
+	for _, kw := range m.Keys() {
+		known := contains(Draft3.keywords, kw) || contains(DraftNext.keywords, kw)
+		if known && !contains(d.keywords, kw) && !contains(annotations, kw) {
+			return nil, renderError(d, joinPtr(ptr, escape(kw)), quote(kw)+" is not supported")
+		}
+	}
+	return wrapRefSiblings(d, m), nil
+}
+
+// wrapRefSiblings moves "$ref" of m into "allOf" ("extends" in draft3),
+// if m has other keywords. Before draft2019-09, keywords next to "$ref"
+// are ignored.
+func wrapRefSiblings(d *Draft, m *OrderedMap) *OrderedMap {
+	if _, ok := m.Get("$ref"); !ok || d.version >= 2019 || len(m.Keys()) == 1 {
+		return m
+	}
+	allOfKw := "allOf"
+	if d.version < 4 {
+		allOfKw = "extends"
+	}
+	wrapped := NewOrderedMap()
+	var allOf []interface{}
+	for _, kw := range m.Keys() {
+		v, _ := m.Get(kw)
+		switch kw {
+		case "$ref":
+			ref := NewOrderedMap()
+			ref.Set(kw, v)
+			allOf = append(allOf, ref)
+		case allOfKw:
+			if arr, ok := v.([]interface{}); ok {
+				allOf = append(allOf, arr...)
+			} else {
+				allOf = append(allOf, v)
+			}
+		default:
+			wrapped.Set(kw, v)
+		}
+	}
+	wrapped.Set(allOfKw, allOf)
+	return wrapped
+}
+
+func renderError(d *Draft, ptr, msg string) error {
+	return fmt.Errorf("jsonschema: cannot render #/%s for %s: %s", ptr, d.url, msg)
+}
+
+// checkValues checks that values of the keywords which are
+// interpreted during rendering, are of expected type. Such values
+// could be of any type, if they are set using Builder.Keyword.
+func (b *Builder) checkValues(d *Draft, ptr string) error {
+	for _, kw := range b.keywords.Keys() {
+		v, _ := b.keywords.Get(kw)
+		var ok bool
+		var want string
+		switch kw {
+		case "$schema", "$id", "$ref", "$anchor", "$dynamicAnchor", "$dynamicRef":
+			_, ok = v.(string)
+			want = "string"
+		case "properties", "$defs", "dependentRequired", "dependentSchemas":
+			_, ok = v.(*OrderedMap)
+			want = "object"
+		case "required":
+			arr, isArr := v.([]interface{})
+			ok = isArr
+			for _, item := range arr {
+				if _, isStr := item.(string); !isStr {
+					ok = false
+				}
+			}
+			want = "array of strings"
+		default:
+			continue
+		}
+		if !ok {
+			return renderError(d, joinPtr(ptr, escape(kw)), "value must be "+want)
+		}
+	}
+	return nil
+}
+
+func (b *Builder) has(kw string) bool {
+	_, ok := b.keywords.Get(kw)
+	return ok
+}
+
+func renderValue(d *Draft, v interface{}, ptr, anchor string) (interface{}, error) {
+	switch v := v.(type) {
+	case *Builder:
+		return v.render(d, ptr, anchor)
+	case []*Builder:
+		return renderValue(d, builders(v), ptr, anchor)
+	case []interface{}:
+		arr := make([]interface{}, len(v))
+		for i, item := range v {
+			var err error
+			if arr[i], err = renderValue(d, item, joinPtr(ptr, strconv.Itoa(i)), anchor); err != nil {
+				return nil, err
+			}
+		}
+		return arr, nil
+	case *OrderedMap:
+		m := NewOrderedMap()
+		for _, k := range v.Keys() {
+			item, _ := v.Get(k)
+			item, err := renderValue(d, item, joinPtr(ptr, escape(k)), anchor)
+			if err != nil {
+				return nil, err
+			}
+			m.Set(k, item)
+		}
+		return m, nil
+	}
+	return v, nil
+}
+
+// AddBuilder adds in-memory resource built by b to the compiler.
+// The document is rendered using Compiler.Draft, unless b specifies
+// its draft using Builder.Draft.
+//
+// Note that url must not have fragment
+func (c *Compiler) AddBuilder(url string, b *Builder) error {
+	doc, err := b.Doc(c.Draft)
+	if err != nil {
+		return err
+	}
+	res, err := newResourceDoc(url, doc)
+	if err != nil {
+		return err
+	}
+	c.resources[res.url] = res
+	return nil
+}
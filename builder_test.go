@@ -0,0 +1,211 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+func TestBuilder(t *testing.T) {
+	b := jsonschema.Object().
+		Def("price", jsonschema.Number().ExclusiveMinimum(0)).
+		Prop("id", jsonschema.String().Format("uuid")).
+		Prop("price", jsonschema.Ref("#/$defs/price").MultipleOf(0.01)).
+		Prop("kind", jsonschema.Any().Const("book")).
+		Prop("tags", jsonschema.Array().PrefixItems(jsonschema.String()).Items(jsonschema.Integer())).
+		Prop("meta", jsonschema.Ref("meta.json")).
+		DependentRequired("price", "id").
+		AdditionalProperties(jsonschema.False()).
+		Required("id")
+	meta := jsonschema.Object().Prop("author", jsonschema.String().MinLength(1))
+
+	tests := []struct {
+		instance string
+		valid    bool
+	}{
+		{`{"id": "7d444840-9dc0-11d1-b245-5ffdce74fad2", "price": 1.5, "kind": "book"}`, true},
+		{`{"price": 1}`, false},
+		{`{"id": "x", "price": 0}`, false},
+		{`{"id": "x", "price": 1.005}`, false},
+		{`{"id": "x", "kind": "pen"}`, false},
+		{`{"id": "7d444840-9dc0-11d1-b245-5ffdce74fad2", "tags": ["a", 1, 2]}`, true},
+		{`{"id": "x", "tags": ["a", "b"]}`, false},
+		{`{"id": "x", "meta": {"author": ""}}`, false},
+		{`{"id": "x", "other": 1}`, false},
+	}
+	for _, draft := range []*jsonschema.Draft{jsonschema.Draft3, jsonschema.Draft4, jsonschema.Draft6, jsonschema.Draft7, jsonschema.Draft2019, jsonschema.Draft2020} {
+		doc, err := b.Doc(draft)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := json.Marshal(doc)
+		t.Logf("%s", data)
+
+		c := jsonschema.NewCompiler()
+		c.Draft = draft
+		if err := c.AddBuilder("schema.json", b); err != nil {
+			t.Fatal(err)
+		}
+		if err := c.AddBuilder("meta.json", meta); err != nil {
+			t.Fatal(err)
+		}
+		sch, err := c.Compile("schema.json")
+		if err != nil {
+			t.Fatalf("%#v", err)
+		}
+		for _, test := range tests {
+			err := sch.Validate(decodeString(t, test.instance))
+			if valid := err == nil; valid != test.valid {
+				t.Errorf("%s: got %v, want %v: %v", test.instance, valid, test.valid, err)
+			}
+		}
+	}
+}
+
+func TestBuilder_Draft(t *testing.T) {
+	b := jsonschema.Any().Draft(jsonschema.Draft7).ID("http://example.com/schema").Const(1)
+	doc, err := b.Doc(jsonschema.Draft4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"$schema":"http://json-schema.org/draft-07/schema","$id":"http://example.com/schema","const":1}`; string(data) != want {
+		t.Errorf("got %s, want %s", data, want)
+	}
+}
+
+func TestBuilder_Draft3Required(t *testing.T) {
+	b := jsonschema.Object().
+		Def("name", jsonschema.String()).
+		Prop("name", jsonschema.Ref("#/$defs/name")).
+		Prop("age", jsonschema.Integer()).
+		Required("name", "id")
+	doc, err := b.Doc(jsonschema.Draft3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"type":"object","definitions":{"name":{"type":"string"}},"properties":{` +
+		`"name":{"extends":{"$ref":"#/definitions/name"},"required":true},"age":{"type":"integer"},"id":{"required":true}}}`
+	if string(data) != want {
+		t.Errorf("got %s, want %s", data, want)
+	}
+
+	c := jsonschema.NewCompiler()
+	c.Draft = jsonschema.Draft3
+	if err := c.AddBuilder("schema.json", b); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.Compile("schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		instance string
+		valid    bool
+	}{
+		{`{"name": "x", "id": 1}`, true},
+		{`{"name": "x"}`, false},
+		{`{"id": 1}`, false},
+		{`{"name": 1, "id": 1}`, false},
+	}
+	for _, test := range tests {
+		err := sch.Validate(decodeString(t, test.instance))
+		if valid := err == nil; valid != test.valid {
+			t.Errorf("%s: got %v, want %v: %v", test.instance, valid, test.valid, err)
+		}
+	}
+}
+
+func TestBuilder_Unsupported(t *testing.T) {
+	tree := func(ref string) *jsonschema.Builder {
+		return jsonschema.Object().
+			DynamicAnchor("node").
+			Prop("children", jsonschema.Array().Items(jsonschema.Any().DynamicRef(ref)))
+	}
+	tests := []struct {
+		b     *jsonschema.Builder
+		draft *jsonschema.Draft
+		err   string // substring of error. empty if valid
+	}{
+		{tree("#node"), jsonschema.Draft2020, ""},
+		{tree("#node"), jsonschema.Draft2019, ""},
+		{tree("#other"), jsonschema.Draft2019, "#/properties/children/items/$dynamicRef"},
+		{tree("#node"), jsonschema.Draft7, "#/$dynamicAnchor"},
+		{jsonschema.Object().Prop("a", jsonschema.Any().DynamicAnchor("a")), jsonschema.Draft2019, "#/properties/a/$dynamicAnchor"},
+		{jsonschema.Any().DynamicRef("#node"), jsonschema.Draft2019, "#/$dynamicRef"},
+		{jsonschema.Array().Contains(jsonschema.Integer()), jsonschema.Draft4, "'contains' is not supported"},
+		{jsonschema.Any().OneOf(jsonschema.Integer()), jsonschema.Draft3, "'oneOf' is not supported"},
+		{jsonschema.Any().Title("x").Examples(1), jsonschema.Draft4, ""},
+		{jsonschema.Any().Keyword("x-custom", 1), jsonschema.Draft4, ""},
+	}
+	for i, test := range tests {
+		doc, err := test.b.Doc(test.draft)
+		if test.err == "" {
+			if err != nil {
+				t.Errorf("#%d: %v", i, err)
+			}
+			continue
+		}
+		if err == nil {
+			data, _ := json.Marshal(doc)
+			t.Errorf("#%d: want error, got %s", i, data)
+		} else if !strings.Contains(err.Error(), test.err) {
+			t.Errorf("#%d: error %q does not contain %q", i, err, test.err)
+		}
+	}
+
+	c := jsonschema.NewCompiler()
+	c.Draft = jsonschema.Draft7
+	if err := c.AddBuilder("schema.json", tree("#node")); err == nil {
+		t.Error("AddBuilder: want error")
+	}
+}
+
+func TestBuilder_InvalidKeyword(t *testing.T) {
+	props := jsonschema.NewOrderedMap()
+	props.Set("a", 1)
+	tests := []struct {
+		b     *jsonschema.Builder
+		draft *jsonschema.Draft
+		err   string
+	}{
+		{jsonschema.Any().Keyword("$ref", 1), jsonschema.Draft7, "#/$ref"},
+		{jsonschema.Any().Keyword("$schema", 1), jsonschema.Draft7, "#/$schema"},
+		{jsonschema.Any().Keyword("$dynamicAnchor", true), jsonschema.Draft2020, "#/$dynamicAnchor"},
+		{jsonschema.Object().Prop("a", jsonschema.Any().Keyword("$anchor", 1)), jsonschema.Draft2020, "#/properties/a/$anchor"},
+		{jsonschema.Object().Keyword("required", "a"), jsonschema.Draft7, "#/required"},
+		{jsonschema.Object().Keyword("properties", 1).Required("a"), jsonschema.Draft3, "#/properties"},
+		{jsonschema.Object().Keyword("dependentRequired", []string{"a"}), jsonschema.Draft7, "#/dependentRequired"},
+		{jsonschema.Object().Keyword("properties", props).Required("a"), jsonschema.Draft3, "#/properties/a"},
+	}
+	for i, test := range tests {
+		if _, err := test.b.Doc(test.draft); err == nil {
+			t.Errorf("#%d: want error", i)
+		} else if !strings.Contains(err.Error(), test.err) {
+			t.Errorf("#%d: error %q does not contain %q", i, err, test.err)
+		}
+	}
+}
+
+func TestBuilder_Type(t *testing.T) {
+	doc, err := jsonschema.String().Type().Doc(jsonschema.Draft7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{}`; string(data) != want {
+		t.Errorf("got %s, want %s", data, want)
+	}
+}
@@ -0,0 +1,271 @@
+package jsonschema
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Bundle returns a single compound schema document for the schema at given url.
+//
+// All external resources reachable through references from that schema are
+// embedded under "$defs" ("definitions" before draft2019-09) of the returned
+// document, with their canonical uri as "$id". The root document is assigned
+// its canonical uri as "$id" if it does not have one. Thus the returned document
+// compiles identically without access to the external resources.
+// Before draft2019-09, where "$id" next to "$ref" is ignored, "$ref" of such
+// schemas is moved into "allOf".
+//
+// References to meta-schemas of supported drafts are not embedded. The
+// embedded resources must use same draft as that of the root document.
+//
+// The returned value is either bool or *OrderedMap.
+func (c *Compiler) Bundle(url string) (interface{}, error) {
+	if _, err := c.Compile(url); err != nil {
+		return nil, err
+	}
+	u, err := toAbs(url)
+	if err != nil {
+		return nil, err
+	}
+	u, _ = split(u)
+	root, err := c.findResource(u)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &bundler{c: c, root: root, rewrites: make(map[*resource]map[string]string)}
+	if err := b.collect(root); err != nil {
+		return nil, err
+	}
+	if len(b.resources) > 0 && root.draft.id == "" {
+		return nil, fmt.Errorf("jsonschema: cannot bundle %s, its draft does not support schema ids", root.url)
+	}
+	return b.bundle(), nil
+}
+
+type bundler struct {
+	c         *Compiler
+	root      *resource
+	resources []*resource                     // embedded resources in discovery order
+	rewrites  map[*resource]map[string]string // refs to be rewritten, keyed by floc of ref
+}
+
+// find returns the resource containing schema with given canonical url.
+func (b *bundler) find(url string) *resource {
+	for _, r := range append([]*resource{b.root}, b.resources...) {
+		if r.findResource(url) != nil {
+			return r
+		}
+	}
+	return nil
+}
+
+// collect collects external resources referred from r.
+func (b *bundler) collect(r *resource) error {
+	flocs := []string{r.floc}
+	for floc := range r.subresources {
+		flocs = append(flocs, floc)
+	}
+	sort.Strings(flocs)
+
+	for _, floc := range flocs {
+		sr := r
+		if floc != r.floc {
+			sr = r.subresources[floc]
+		}
+		m, ok := sr.doc.(*OrderedMap)
+		if !ok {
+			continue
+		}
+		for _, kw := range refKeywords(r.draft) {
+			ref, ok := m.Get(kw)
+			if !ok {
+				continue
+			}
+			ref, ok = ref.(string)
+			if !ok {
+				continue
+			}
+			abs, err := resolveURL(r.baseURL(floc), ref.(string))
+			if err != nil {
+				return err
+			}
+			u, f := split(abs)
+			if findDraft(u) != nil || b.find(u) != nil {
+				continue
+			}
+			ext, err := b.c.findResource(u)
+			if err != nil {
+				return err
+			}
+			if ext.url != u {
+				// resource has $id different from its retrieval url.
+				if b.rewrites[r] == nil {
+					b.rewrites[r] = make(map[string]string)
+				}
+				if f == "#" {
+					f = ""
+				}
+				b.rewrites[r][floc+"/"+escape(kw)] = ext.url + f
+			}
+			if b.find(ext.url) != nil {
+				continue
+			}
+			if ext.draft != b.root.draft {
+				return fmt.Errorf("jsonschema: cannot bundle %s, it uses draft different from %s", ext.url, b.root.url)
+			}
+			b.resources = append(b.resources, ext)
+			if err := b.collect(ext); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (b *bundler) bundle() interface{} {
+	d := b.root.draft
+	doc := copyDoc(b.root.doc, "#", b.rewrites[b.root])
+	if len(b.resources) == 0 {
+		return doc
+	}
+
+	m, ok := doc.(*OrderedMap)
+	if !ok {
+		// boolean schema does not refer anything
+		return doc
+	}
+	if d.version < 2019 {
+		m = wrapRef(m)
+	}
+	result := NewOrderedMap()
+	if sch, ok := m.Get("$schema"); ok {
+		result.Set("$schema", sch)
+	}
+	if d.getID(m) == "" {
+		result.Set(d.id, b.root.url)
+	}
+	for _, k := range m.Keys() {
+		v, _ := m.Get(k)
+		result.Set(k, v)
+	}
+
+	defsKey := "$defs"
+	if d.version < 2019 {
+		defsKey = "definitions"
+	}
+	defs := NewOrderedMap()
+	if v, ok := result.Get(defsKey); ok {
+		if v, ok := v.(*OrderedMap); ok {
+			defs = v
+		}
+	}
+	for _, r := range b.resources {
+		var sch *OrderedMap
+		switch v := copyDoc(r.doc, "#", b.rewrites[r]).(type) {
+		case *OrderedMap:
+			if d.version < 2019 {
+				v = wrapRef(v)
+			}
+			sch = NewOrderedMap()
+			sch.Set(d.id, r.url)
+			for _, k := range v.Keys() {
+				if k != d.id {
+					kv, _ := v.Get(k)
+					sch.Set(k, kv)
+				}
+			}
+		case bool:
+			// boolean schema cannot have $id
+			sch = NewOrderedMap()
+			sch.Set(d.id, r.url)
+			if !v {
+				sch.Set("not", NewOrderedMap())
+			}
+		}
+		defs.Set(defName(defs, r.url), sch)
+	}
+	result.Set(defsKey, defs)
+	return result
+}
+
+// wrapRef returns schema m with its "$ref" moved into "allOf". It is used
+// before draft2019-09, where "$id" next to "$ref" is ignored. Since other
+// keywords next to "$ref" are ignored too, only the keywords which do not
+// affect validation are retained.
+func wrapRef(m *OrderedMap) *OrderedMap {
+	ref, ok := m.Get("$ref")
+	if !ok {
+		return m
+	}
+	w := NewOrderedMap()
+	for _, k := range m.Keys() {
+		if contains(refSiblings, k) {
+			v, _ := m.Get(k)
+			w.Set(k, v)
+		}
+	}
+	allOf := NewOrderedMap()
+	allOf.Set("$ref", ref)
+	w.Set("allOf", []interface{}{allOf})
+	return w
+}
+
+// defName returns unique name in defs, for the resource at url.
+func defName(defs *OrderedMap, url string) string {
+	u, _ := split(url)
+	name := path.Base(strings.TrimSuffix(u, "/"))
+	name = strings.TrimSuffix(name, path.Ext(name))
+	if name == "" || name == "." || name == "/" {
+		name = "resource"
+	}
+	unique := name
+	for i := 2; ; i++ {
+		if _, ok := defs.Get(unique); !ok {
+			return unique
+		}
+		unique = name + "-" + strconv.Itoa(i)
+	}
+}
+
+// refKeywords returns keywords holding references in given draft.
+func refKeywords(d *Draft) []string {
+	switch {
+	case d.version >= 2020:
+		return []string{"$ref", "$dynamicRef"}
+	case d.version >= 2019:
+		return []string{"$ref", "$recursiveRef"}
+	default:
+		return []string{"$ref"}
+	}
+}
+
+// copyDoc returns deep copy of json value v located at floc.
+// values at locations found in rewrites are replaced.
+func copyDoc(v interface{}, floc string, rewrites map[string]string) interface{} {
+	switch v := v.(type) {
+	case *OrderedMap:
+		m := NewOrderedMap()
+		for _, k := range v.Keys() {
+			kv, _ := v.Get(k)
+			kloc := floc + "/" + escape(k)
+			if rw, ok := rewrites[kloc]; ok {
+				m.Set(k, rw)
+			} else {
+				m.Set(k, copyDoc(kv, kloc, rewrites))
+			}
+		}
+		return m
+	case []interface{}:
+		arr := make([]interface{}, len(v))
+		for i, item := range v {
+			arr[i] = copyDoc(item, floc+"/"+strconv.Itoa(i), rewrites)
+		}
+		return arr
+	default:
+		return v
+	}
+}
@@ -0,0 +1,163 @@
+package jsonschema_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+func TestCompiler_Bundle(t *testing.T) {
+	files := map[string]string{
+		"map:///root.json": `{
+			"type": "object",
+			"properties": {
+				"customer": {"$ref": "customer.json"},
+				"price": {"$ref": "types/common.json#/$defs/price"}
+			},
+			"$defs": {"local": {"type": "string"}}
+		}`,
+		"map:///customer.json": `{
+			"properties": {
+				"name": {"$ref": "#/$defs/local"},
+				"address": {"$ref": "address.json"},
+				"parent": {"$ref": "root.json"}
+			},
+			"$defs": {"local": {"minLength": 1}}
+		}`,
+		"map:///address.json": `{
+			"$id": "https://example.com/schemas/address",
+			"properties": {"zip": {"type": "string", "pattern": "^[0-9]{5}$"}}
+		}`,
+		"map:///types/common.json": `{
+			"$defs": {"price": {"type": "number", "minimum": 0}}
+		}`,
+	}
+	loadURL := func(s string) (io.ReadCloser, error) {
+		if f, ok := files[s]; ok {
+			return ioutil.NopCloser(strings.NewReader(f)), nil
+		}
+		return nil, errors.New("not found")
+	}
+
+	c := jsonschema.NewCompiler()
+	c.LoadURL = loadURL
+	sch, err := c.Compile("map:///root.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := c.Bundle("map:///root.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Logf("%s", b)
+
+	// compile bundle offline
+	bc := jsonschema.NewCompiler()
+	bc.LoadURL = func(s string) (io.ReadCloser, error) {
+		return nil, errors.New("offline")
+	}
+	if err := bc.AddResource("bundle.json", bytes.NewReader(b)); err != nil {
+		t.Fatal(err)
+	}
+	bsch, err := bc.Compile("bundle.json")
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+
+	instances := []string{
+		`{"price": 10, "customer": {"name": "x", "address": {"zip": "12345"}}}`,
+		`{"price": -1}`,
+		`{"customer": {"name": ""}}`,
+		`{"customer": {"name": 1}}`,
+		`{"customer": {"address": {"zip": "x"}}}`,
+		`{"customer": {"parent": {"price": -1}}}`,
+		`{"customer": {"parent": {"price": 1}}}`,
+	}
+	for _, instance := range instances {
+		want := sch.Validate(decodeString(t, instance)) == nil
+		got := bsch.Validate(decodeString(t, instance)) == nil
+		if got != want {
+			t.Errorf("%s: got %v, want %v", instance, got, want)
+		}
+	}
+}
+
+func TestCompiler_BundleDraftMismatch(t *testing.T) {
+	c := jsonschema.NewCompiler()
+	if err := c.AddResource("a.json", strings.NewReader(`{"$ref": "b.json"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.AddResource("b.json", strings.NewReader(`{"$schema": "http://json-schema.org/draft-07/schema#"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Bundle("a.json"); err == nil {
+		t.Fatal("error expected")
+	} else {
+		t.Log(err)
+	}
+}
+
+func TestCompiler_BundleRefOnly(t *testing.T) {
+	files := map[string]string{
+		"map:///root.json": `{"properties": {"a": {"$ref": "a.json"}}}`,
+		"map:///ref.json":  `{"$ref": "a.json", "type": "string"}`,
+		"map:///a.json":    `{"$ref": "b.json", "type": "string", "definitions": {"pos": {"minimum": 0}}}`,
+		"map:///b.json":    `{"type": "integer", "allOf": [{"$ref": "a.json#/definitions/pos"}]}`,
+	}
+	loadURL := func(s string) (io.ReadCloser, error) {
+		if f, ok := files[s]; ok {
+			return ioutil.NopCloser(strings.NewReader(f)), nil
+		}
+		return nil, errors.New("not found")
+	}
+	for _, draft := range []*jsonschema.Draft{jsonschema.Draft4, jsonschema.Draft6, jsonschema.Draft7} {
+		for _, root := range []string{"map:///root.json", "map:///ref.json"} {
+			c := jsonschema.NewCompiler()
+			c.Draft = draft
+			c.LoadURL = loadURL
+			sch, err := c.Compile(root)
+			if err != nil {
+				t.Fatal(err)
+			}
+			doc, err := c.Bundle(root)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := json.Marshal(doc)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// compile bundle offline
+			bc := jsonschema.NewCompiler()
+			bc.Draft = draft
+			bc.LoadURL = func(s string) (io.ReadCloser, error) {
+				return nil, errors.New("offline")
+			}
+			if err := bc.AddResource("bundle.json", bytes.NewReader(b)); err != nil {
+				t.Fatal(err)
+			}
+			bsch, err := bc.Compile("bundle.json")
+			if err != nil {
+				t.Fatalf("%s: %#v\n%s", root, err, b)
+			}
+			for _, instance := range []string{`{"a": 1}`, `{"a": -1}`, `{"a": 1.5}`, `{"a": "x"}`, `1`, `-1`, `"x"`} {
+				want := sch.Validate(decodeString(t, instance)) == nil
+				got := bsch.Validate(decodeString(t, instance)) == nil
+				if got != want {
+					t.Errorf("%s: %s: got %v, want %v\n%s", root, instance, got, want, b)
+				}
+			}
+		}
+	}
+}
@@ -4,20 +4,55 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
+	"io/ioutil"
 	"os"
+	"strings"
 
 	"github.com/santhosh-tekuri/jsonschema/v5"
 	_ "github.com/santhosh-tekuri/jsonschema/v5/httploader"
 )
 
 func usage() {
-	fmt.Fprintln(os.Stderr, "jv [-draft INT] [-output FORMAT] <json-schema> [<json-doc>]...")
+	fmt.Fprintln(os.Stderr, "jv [-draft INT] [-output FORMAT] [-strict] <json-schema> [<json-doc>]...")
+	fmt.Fprintln(os.Stderr, "jv migrate [-from INT] [-to INT] <json-schema>")
+	fmt.Fprintln(os.Stderr, "jv lint [-draft INT] [-output FORMAT] [-disable RULES] <json-schema>...")
 	flag.PrintDefaults()
 }
 
+func toDraft(draft int) *jsonschema.Draft {
+	switch draft {
+	case 3:
+		return jsonschema.Draft3
+	case 4:
+		return jsonschema.Draft4
+	case 6:
+		return jsonschema.Draft6
+	case 7:
+		return jsonschema.Draft7
+	case 2019:
+		return jsonschema.Draft2019
+	case 2020:
+		return jsonschema.Draft2020
+	}
+	fmt.Fprintln(os.Stderr, "draft must be 3, 4, 6, 7, 2019 or 2020")
+	os.Exit(1)
+	return nil
+}
+
 func main() {
-	draft := flag.Int("draft", 2020, "draft used when '$schema' attribute is missing. valid values 4, 5, 7, 2019, 2020")
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		migrate(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "lint" {
+		lint(os.Args[2:])
+		return
+	}
+
+	draft := flag.Int("draft", 2020, "draft used when '$schema' attribute is missing. valid values 3, 4, 6, 7, 2019, 2020")
 	output := flag.String("output", "", "output format. valid values flag, basic, detailed")
+	locale := flag.String("locale", "en", "language of error messages. valid values en, de, ja")
+	strict := flag.Bool("strict", false, "fail on unknown keywords and formats, ignored keywords and type incompatible keywords")
 	flag.Usage = usage
 	flag.Parse()
 	if len(flag.Args()) == 0 {
@@ -26,21 +61,8 @@ func main() {
 	}
 
 	compiler := jsonschema.NewCompiler()
-	switch *draft {
-	case 4:
-		compiler.Draft = jsonschema.Draft4
-	case 6:
-		compiler.Draft = jsonschema.Draft6
-	case 7:
-		compiler.Draft = jsonschema.Draft7
-	case 2019:
-		compiler.Draft = jsonschema.Draft2019
-	case 2020:
-		compiler.Draft = jsonschema.Draft2020
-	default:
-		fmt.Fprintln(os.Stderr, "draft must be 4, 5, 7, 2019 or 2020")
-		os.Exit(1)
-	}
+	compiler.Draft = toDraft(*draft)
+	compiler.Strict = *strict
 
 	var validOutput bool
 	for _, out := range []string{"", "flag", "basic", "detailed"} {
@@ -54,6 +76,13 @@ func main() {
 		os.Exit(1)
 	}
 
+	catalog, ok := jsonschema.Catalogs[*locale]
+	if !ok {
+		fmt.Fprintln(os.Stderr, "locale must be en, de or ja")
+		os.Exit(1)
+	}
+	compiler.Translator = catalog
+
 	schema, err := compiler.Compile(flag.Arg(0))
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "%#v\n", err)
@@ -100,3 +129,98 @@ func main() {
 		}
 	}
 }
+
+func migrate(args []string) {
+	flags := flag.NewFlagSet("migrate", flag.ExitOnError)
+	from := flags.Int("from", 4, "draft used when '$schema' attribute is missing. valid values 3, 4, 6, 7, 2019, 2020")
+	to := flags.Int("to", 2020, "draft to migrate to. valid values 3, 4, 6, 7, 2019, 2020")
+	flags.Usage = usage
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		usage()
+		os.Exit(1)
+	}
+
+	b, err := ioutil.ReadFile(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	var doc interface{}
+	m := jsonschema.NewOrderedMap()
+	m.SetUseNumber(true)
+	if err := json.Unmarshal(b, m); err == nil {
+		doc = m
+	} else if err := json.Unmarshal(b, &doc); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid json file %s: %v\n", flags.Arg(0), err)
+		os.Exit(1)
+	}
+
+	doc, err = jsonschema.Migrate(doc, toDraft(*from), toDraft(*to))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%#v\n", err)
+		os.Exit(1)
+	}
+	b, _ = json.MarshalIndent(doc, "", "  ")
+	fmt.Println(string(b))
+}
+
+func lint(args []string) {
+	flags := flag.NewFlagSet("lint", flag.ExitOnError)
+	draft := flags.Int("draft", 2020, "draft used when '$schema' attribute is missing. valid values 3, 4, 6, 7, 2019, 2020")
+	output := flags.String("output", "text", "output format. valid values text, json")
+	disable := flags.String("disable", "", "comma separated names of lint rules to disable")
+	flags.Usage = usage
+	flags.Parse(args)
+	if flags.NArg() == 0 {
+		usage()
+		os.Exit(1)
+	}
+	if *output != "text" && *output != "json" {
+		fmt.Fprintln(os.Stderr, "output must be text or json")
+		os.Exit(1)
+	}
+
+	disabled := strings.Split(*disable, ",")
+	var rules []jsonschema.LintRule
+	for _, rule := range jsonschema.DefaultLintRules() {
+		enabled := true
+		for _, name := range disabled {
+			if strings.TrimSpace(name) == rule.Name {
+				enabled = false
+				break
+			}
+		}
+		if enabled {
+			rules = append(rules, rule)
+		}
+	}
+
+	lints := []jsonschema.Lint{}
+	var failed bool
+	for _, f := range flags.Args() {
+		compiler := jsonschema.NewCompiler()
+		compiler.Draft = toDraft(*draft)
+		l, err := compiler.Lint(f, rules)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%#v\n", err)
+			os.Exit(1)
+		}
+		for _, lint := range l {
+			failed = failed || lint.Severity == jsonschema.LintError
+		}
+		lints = append(lints, l...)
+	}
+
+	if *output == "json" {
+		b, _ := json.MarshalIndent(lints, "", "  ")
+		fmt.Println(string(b))
+	} else {
+		for _, lint := range lints {
+			fmt.Println(lint)
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
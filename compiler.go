@@ -17,6 +17,7 @@ type Compiler struct {
 	// This defaults to latest draft (currently draft2019-09).
 	Draft     *Draft
 	resources map[string]*resource
+	dialects  map[string]*Draft // custom meta-schema url to dialect
 
 	// Extensions is used to register extensions.
 	extensions map[string]extension
@@ -35,6 +36,57 @@ type Compiler struct {
 
 	// AssertContent for specifications >= draft2019-09.
 	AssertContent bool
+
+	// Discriminator enables OpenAPI "discriminator" keyword in json-schema drafts.
+	// It is always enabled in OpenAPI30 and OpenAPI31.
+	//
+	// When used along with oneOf/anyOf, the value of discriminator property selects
+	// the alternative to be validated, instead of trying all alternatives.
+	Discriminator bool
+
+	// ErrorMessage enables "errorMessage" keyword, which overrides the messages
+	// of validation errors. See ErrorMessage for details.
+	ErrorMessage bool
+
+	// Data enables ajv-style "$data" references, which are resolved at
+	// validation time. For example {"maximum": {"$data": "1/maxPrice"}}.
+	//
+	// "$data" value is either json-pointer from the root of instance, or
+	// relative-json-pointer from the instance being validated. It is allowed only
+	// in "const", "enum", "format", "pattern", "minimum", "maximum",
+	// "exclusiveMinimum", "exclusiveMaximum", "multipleOf", "minLength",
+	// "maxLength", "minItems", "maxItems", "minProperties" and "maxProperties".
+	// If referenced value is not found, the keyword is ignored.
+	Data bool
+
+	// CheckValues validates the values of "default" and "examples", and the
+	// members of "const" and "enum", against the schema in which they appear.
+	// Violations are reported as *ValueError.
+	//
+	// If Warn is nil, the first violation fails the compilation. Otherwise
+	// all violations are passed to Warn, and the compilation succeeds.
+	CheckValues bool
+
+	// Warn, if not nil, is called with the problems which are reported
+	// as warnings instead of errors. See CheckValues.
+	Warn func(err error)
+
+	// Strict fails the compilation on the problems, which are otherwise
+	// ignored silently:
+	//   - keywords that are not known to the draft, and not claimed by
+	//     any registered extension. Extensions claim the properties of their
+	//     meta-schema.
+	//   - unknown formats
+	//   - keywords along with "$ref", which are ignored before draft2019-09.
+	//     annotations and "definitions" are allowed.
+	//   - keywords not applicable to the types in "type", such as "minLength"
+	//     along with "type": "integer"
+	Strict bool
+
+	// Translator translates the messages of validation errors, of the schemas
+	// compiled by this compiler. nil means English. See Catalogs for built-in
+	// translations.
+	Translator Translator
 }
 
 // Compile parses json-schema at given url returns, if successful,
@@ -74,7 +126,7 @@ func MustCompileString(url, schema string) *Schema {
 // if '$schema' attribute is missing, it is treated as draft7. to change this
 // behavior change Compiler.Draft value
 func NewCompiler() *Compiler {
-	return &Compiler{Draft: latest, resources: make(map[string]*resource), extensions: make(map[string]extension)}
+	return &Compiler{Draft: latest, resources: make(map[string]*resource), dialects: make(map[string]*Draft), extensions: make(map[string]extension)}
 }
 
 // AddResource adds in-memory resource to the compiler.
@@ -112,6 +164,9 @@ func (c *Compiler) Compile(url string) (*Schema, error) {
 	url = u
 
 	sch, err := c.compileURL(url, nil, "#")
+	if err == nil && c.CheckValues {
+		err = c.checkValues()
+	}
 	if err != nil {
 		err = &SchemaError{url, err}
 	}
@@ -140,6 +195,13 @@ func (c *Compiler) findResource(url string) (*resource, error) {
 		return r, nil
 	}
 
+	if isOpenAPI(r.doc) {
+		if err := c.loadOpenAPI(r); err != nil {
+			return nil, err
+		}
+		return r, nil
+	}
+
 	// set draft
 	r.draft = c.Draft
 	if m, ok := r.doc.(*OrderedMap); ok {
@@ -147,10 +209,11 @@ func (c *Compiler) findResource(url string) (*resource, error) {
 			if _, ok = sch.(string); !ok {
 				return nil, fmt.Errorf("jsonschema: invalid $schema in %s", url)
 			}
-			r.draft = findDraft(sch.(string))
-			if r.draft == nil {
-				return nil, fmt.Errorf("jsonschema: invalid $schema in %s", url)
+			d, err := c.findDialect(sch.(string))
+			if err != nil {
+				return nil, fmt.Errorf("jsonschema: invalid $schema in %s: %v", url, err)
 			}
+			r.draft = d
 		}
 	}
 
@@ -212,6 +275,8 @@ func (c *Compiler) compileRef(r *resource, stack []schemaRef, refPtr string, res
 	}
 
 	sr.schema = newSchema(r.url, sr.floc, sr.doc)
+	sr.schema.draft = r.draft
+	sr.schema.translator = c.Translator
 	return c.compile(r, stack, schemaRef{refPtr, sr.schema, false}, sr)
 }
 
@@ -251,7 +316,7 @@ func (c *Compiler) compile(r *resource, stack []schemaRef, sref schemaRef, res *
 }
 
 func (c *Compiler) compileMap(r *resource, stack []schemaRef, sref schemaRef, res *resource) error {
-	m := res.doc.(*OrderedMap)
+	m := r.draft.filterKeywords(res.doc.(*OrderedMap))
 
 	if err := checkLoop(stack, sref); err != nil {
 		return err
@@ -261,6 +326,17 @@ func (c *Compiler) compileMap(r *resource, stack []schemaRef, sref schemaRef, re
 	var s = res.schema
 	var err error
 
+	if c.Strict {
+		if err := c.checkStrict(r, res, m); err != nil {
+			return err
+		}
+	}
+	if c.Data {
+		if m, s.Data, err = compileData(r, res, m); err != nil {
+			return err
+		}
+	}
+
 	if ref, ok := m.Get("$ref"); ok {
 		s.Ref, err = c.compileRef(r, stack, "$ref", res, ref.(string))
 		if err != nil {
@@ -289,7 +365,7 @@ func (c *Compiler) compileMap(r *resource, stack []schemaRef, sref schemaRef, re
 		}
 	}
 
-	if t, ok := m.Get("type"); ok {
+	if t, ok := m.Get("type"); ok && r.draft.version >= 4 {
 		switch t := t.(type) {
 		case string:
 			s.Types = []string{t}
@@ -300,26 +376,6 @@ func (c *Compiler) compileMap(r *resource, stack []schemaRef, sref schemaRef, re
 
 	if e, ok := m.Get("enum"); ok {
 		s.Enum = e.([]interface{})
-		allPrimitives := true
-		for _, item := range s.Enum {
-			switch jsonType(item) {
-			case "object", "array":
-				allPrimitives = false
-				break
-			}
-		}
-		s.enumError = "enum failed"
-		if allPrimitives {
-			if len(s.Enum) == 1 {
-				s.enumError = fmt.Sprintf("value must be %#v", s.Enum[0])
-			} else {
-				strEnum := make([]string, len(s.Enum))
-				for i, item := range s.Enum {
-					strEnum[i] = fmt.Sprintf("%#v", item)
-				}
-				s.enumError = fmt.Sprintf("value must be one of %s", strings.Join(strEnum, ", "))
-			}
-		}
 	}
 
 	compile := func(stack []schemaRef, ptr string) (*Schema, error) {
@@ -333,10 +389,6 @@ func (c *Compiler) compileMap(r *resource, stack []schemaRef, sref schemaRef, re
 		return nil, nil
 	}
 
-	if s.Not, err = loadSchema("not", stack); err != nil {
-		return err
-	}
-
 	loadSchemas := func(pname string, stack []schemaRef) ([]*Schema, error) {
 		if pvalue, ok := m.Get(pname); ok {
 			pvalue := pvalue.([]interface{})
@@ -352,14 +404,90 @@ func (c *Compiler) compileMap(r *resource, stack []schemaRef, sref schemaRef, re
 		}
 		return nil, nil
 	}
-	if s.AllOf, err = loadSchemas("allOf", stack); err != nil {
-		return err
+
+	if r.draft.version < 4 {
+		// draft3: type and disallow can have schemas along with type names
+		loadTypes := func(pname string) ([]string, []*Schema, error) {
+			pvalue, ok := m.Get(pname)
+			if !ok {
+				return nil, nil, nil
+			}
+			arr, ok := pvalue.([]interface{})
+			if !ok {
+				return []string{pvalue.(string)}, nil, nil
+			}
+			var types []string
+			var schemas []*Schema
+			for i, item := range arr {
+				if t, ok := item.(string); ok {
+					types = append(types, t)
+					continue
+				}
+				sch, err := compile(stack, escape(pname)+"/"+strconv.Itoa(i))
+				if err != nil {
+					return nil, nil, err
+				}
+				schemas = append(schemas, sch)
+			}
+			return types, schemas, nil
+		}
+		if s.Types, s.TypeSchemas, err = loadTypes("type"); err != nil {
+			return err
+		}
+		if s.Disallow, s.DisallowSchemas, err = loadTypes("disallow"); err != nil {
+			return err
+		}
+		if extends, ok := m.Get("extends"); ok {
+			if _, ok := extends.([]interface{}); ok {
+				s.Extends, err = loadSchemas("extends", stack)
+			} else {
+				var sch *Schema
+				sch, err = compile(stack, "extends")
+				s.Extends = []*Schema{sch}
+			}
+			if err != nil {
+				return err
+			}
+		}
+	} else {
+		if s.Not, err = loadSchema("not", stack); err != nil {
+			return err
+		}
+		if s.AllOf, err = loadSchemas("allOf", stack); err != nil {
+			return err
+		}
+		if s.AnyOf, err = loadSchemas("anyOf", stack); err != nil {
+			return err
+		}
+		if s.OneOf, err = loadSchemas("oneOf", stack); err != nil {
+			return err
+		}
 	}
-	if s.AnyOf, err = loadSchemas("anyOf", stack); err != nil {
-		return err
+
+	if d, ok := m.Get("discriminator"); ok && (r.draft.openapi || c.Discriminator) {
+		if err := c.compileDiscriminator(r, stack, res, d); err != nil {
+			return err
+		}
 	}
-	if s.OneOf, err = loadSchemas("oneOf", stack); err != nil {
-		return err
+
+	if r.draft.openapi {
+		if nullable, ok := m.Get("nullable"); ok && r.draft.version < 2019 {
+			s.Nullable = nullable.(bool)
+		}
+		if example, ok := m.Get("example"); ok && c.ExtractAnnotations {
+			s.Examples = []interface{}{example}
+		}
+		if c.ExtractAnnotations && r.draft.version < 2019 {
+			if readOnly, ok := m.Get("readOnly"); ok {
+				s.ReadOnly = readOnly.(bool)
+			}
+			if writeOnly, ok := m.Get("writeOnly"); ok {
+				s.WriteOnly = writeOnly.(bool)
+			}
+			if deprecated, ok := m.Get("deprecated"); ok {
+				s.Deprecated = deprecated.(bool)
+			}
+		}
 	}
 
 	loadInt := func(pname string) int {
@@ -369,10 +497,12 @@ func (c *Compiler) compileMap(r *resource, stack []schemaRef, sref schemaRef, re
 		}
 		return -1
 	}
-	s.MinProperties, s.MaxProperties = loadInt("minProperties"), loadInt("maxProperties")
+	if r.draft.version >= 4 {
+		s.MinProperties, s.MaxProperties = loadInt("minProperties"), loadInt("maxProperties")
 
-	if req, ok := m.Get("required"); ok {
-		s.Required = toStrings(req.([]interface{}))
+		if req, ok := m.Get("required"); ok {
+			s.Required = toStrings(req.([]interface{}))
+		}
 	}
 
 	if props, ok := m.Get("properties"); ok {
@@ -385,6 +515,15 @@ func (c *Compiler) compileMap(r *resource, stack []schemaRef, sref schemaRef, re
 			}
 
 			s.Properties.Set(pname, val)
+
+			if r.draft.version < 4 {
+				// draft3: required is boolean in property schema
+				if pm, ok := props.RawValues()[pname].(*OrderedMap); ok {
+					if req, _ := pm.Get("required"); req == true {
+						s.Required = append(s.Required, pname)
+					}
+				}
+			}
 		}
 	}
 
@@ -396,7 +535,9 @@ func (c *Compiler) compileMap(r *resource, stack []schemaRef, sref schemaRef, re
 		patternProps := patternProps.(*OrderedMap)
 		s.PatternProperties = make(map[*regexp.Regexp]*Schema, len(patternProps.Keys()))
 		for _, pattern := range patternProps.Keys() {
-			s.PatternProperties[regexp.MustCompile(pattern)], err = compile(nil, "patternProperties/"+escape(pattern))
+			re := regexp.MustCompile(pattern)
+			s.patterns = append(s.patterns, re)
+			s.PatternProperties[re], err = compile(nil, "patternProperties/"+escape(pattern))
 			if err != nil {
 				return err
 			}
@@ -417,16 +558,21 @@ func (c *Compiler) compileMap(r *resource, stack []schemaRef, sref schemaRef, re
 
 	if deps, ok := m.Get("dependencies"); ok {
 		deps := deps.(*OrderedMap)
-		s.Dependencies = make(map[string]interface{}, len(deps.Keys()))
-		for pname, pvalue := range deps.RawValues() {
+		s.Dependencies = NewOrderedMap()
+		for _, pname := range deps.Keys() {
+			pvalue, _ := deps.Get(pname)
 			switch pvalue := pvalue.(type) {
+			case string:
+				// draft3: single property dependency
+				s.Dependencies.Set(pname, []string{pvalue})
 			case []interface{}:
-				s.Dependencies[pname] = toStrings(pvalue)
+				s.Dependencies.Set(pname, toStrings(pvalue))
 			default:
-				s.Dependencies[pname], err = compile(stack, "dependencies/"+escape(pname))
+				sch, err := compile(stack, "dependencies/"+escape(pname))
 				if err != nil {
 					return err
 				}
+				s.Dependencies.Set(pname, sch)
 			}
 		}
 	}
@@ -434,19 +580,40 @@ func (c *Compiler) compileMap(r *resource, stack []schemaRef, sref schemaRef, re
 	if r.draft.version >= 2019 {
 		if deps, ok := m.Get("dependentRequired"); ok {
 			deps := deps.(*OrderedMap)
-			s.DependentRequired = make(map[string][]string, len(deps.Keys()))
-			for pname, pvalue := range deps.RawValues() {
-				s.DependentRequired[pname] = toStrings(pvalue.([]interface{}))
+			s.DependentRequired = NewOrderedMap()
+			for _, pname := range deps.Keys() {
+				pvalue, _ := deps.Get(pname)
+				s.DependentRequired.Set(pname, toStrings(pvalue.([]interface{})))
 			}
 		}
 		if deps, ok := m.Get("dependentSchemas"); ok {
 			deps := deps.(*OrderedMap)
-			s.DependentSchemas = make(map[string]*Schema, len(deps.Keys()))
+			s.DependentSchemas = NewOrderedMap()
 			for _, pname := range deps.Keys() {
-				s.DependentSchemas[pname], err = compile(stack, "dependentSchemas/"+escape(pname))
+				sch, err := compile(stack, "dependentSchemas/"+escape(pname))
 				if err != nil {
 					return err
 				}
+				s.DependentSchemas.Set(pname, sch)
+			}
+		}
+		if r.draft.version > 2020 {
+			if deps, ok := m.Get("propertyDependencies"); ok {
+				deps := deps.(*OrderedMap)
+				s.PropertyDependencies = NewOrderedMap()
+				for _, pname := range deps.Keys() {
+					pvalue, _ := deps.Get(pname)
+					values := pvalue.(*OrderedMap)
+					schemas := NewOrderedMap()
+					for _, value := range values.Keys() {
+						sch, err := compile(stack, "propertyDependencies/"+escape(pname)+"/"+escape(value))
+						if err != nil {
+							return err
+						}
+						schemas.Set(value, sch)
+					}
+					s.PropertyDependencies.Set(pname, schemas)
+				}
 			}
 		}
 		if s.UnevaluatedProperties, err = loadSchema("unevaluatedProperties", nil); err != nil {
@@ -507,6 +674,15 @@ func (c *Compiler) compileMap(r *resource, stack []schemaRef, sref schemaRef, re
 	if format, ok := m.Get("format"); ok {
 		s.Format = format.(string)
 		s.format, _ = Formats[s.Format]
+		if r.draft.version < 4 {
+			// draft3 names of formats renamed in draft4
+			switch s.Format {
+			case "ip-address":
+				s.format = Formats["ipv4"]
+			case "host-name":
+				s.format = Formats["hostname"]
+			}
+		}
 	}
 
 	loadRat := func(pname string) *big.Rat {
@@ -522,6 +698,7 @@ func (c *Compiler) compileMap(r *resource, stack []schemaRef, sref schemaRef, re
 		if exclusive, ok := exclusive.(bool); ok {
 			if exclusive {
 				s.Minimum, s.ExclusiveMinimum = nil, s.Minimum
+				_, s.dataExclusiveMinimum = s.Data["minimum"]
 			}
 		} else {
 			s.ExclusiveMinimum = loadRat("exclusiveMinimum")
@@ -533,13 +710,18 @@ func (c *Compiler) compileMap(r *resource, stack []schemaRef, sref schemaRef, re
 		if exclusive, ok := exclusive.(bool); ok {
 			if exclusive {
 				s.Maximum, s.ExclusiveMaximum = nil, s.Maximum
+				_, s.dataExclusiveMaximum = s.Data["maximum"]
 			}
 		} else {
 			s.ExclusiveMaximum = loadRat("exclusiveMaximum")
 		}
 	}
 
-	s.MultipleOf = loadRat("multipleOf")
+	if r.draft.version < 4 {
+		s.MultipleOf = loadRat("divisibleBy")
+	} else {
+		s.MultipleOf = loadRat("multipleOf")
+	}
 
 	if c.ExtractAnnotations {
 		if title, ok := m.Get("title"); ok {
@@ -607,8 +789,9 @@ func (c *Compiler) compileMap(r *resource, stack []schemaRef, sref schemaRef, re
 	if r.draft.version >= 2019 {
 		s.decoder = nil
 		s.mediaType = nil
-		if !c.AssertFormat {
+		if !c.AssertFormat && !r.draft.hasVocab("format-assertion") {
 			s.format = nil
+			delete(s.Data, "format")
 		}
 
 		s.MinContains, s.MaxContains = loadInt("minContains"), loadInt("maxContains")
@@ -623,7 +806,16 @@ func (c *Compiler) compileMap(r *resource, stack []schemaRef, sref schemaRef, re
 		}
 	}
 
+	if em, ok := m.Get("errorMessage"); ok && c.ErrorMessage {
+		if s.ErrorMessage, err = compileErrorMessage(res, m, em); err != nil {
+			return err
+		}
+	}
+
 	for name, ext := range c.extensions {
+		if !r.draft.isExtEnabled(name) {
+			continue
+		}
 		es, err := ext.compiler.Compile(CompilerContext{c, r, stack, res}, m)
 		if err != nil {
 			return err
@@ -640,6 +832,9 @@ func (c *Compiler) compileMap(r *resource, stack []schemaRef, sref schemaRef, re
 }
 
 func (c *Compiler) validateSchema(r *resource, v interface{}, vloc string) error {
+	if c.Data {
+		v = stripData(r.draft, v)
+	}
 	validate := func(meta *Schema) error {
 		if meta == nil {
 			return nil
@@ -650,7 +845,10 @@ func (c *Compiler) validateSchema(r *resource, v interface{}, vloc string) error
 	if err := validate(r.draft.meta); err != nil {
 		return err
 	}
-	for _, ext := range c.extensions {
+	for name, ext := range c.extensions {
+		if !r.draft.isExtEnabled(name) {
+			continue
+		}
 		if err := validate(ext.meta); err != nil {
 			return err
 		}
@@ -0,0 +1,263 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// dataKeywords returns the keywords of given draft, whose value can be
+// "$data" reference.
+func dataKeywords(d *Draft) []string {
+	keywords := []string{"enum", "format", "minimum", "maximum", "minLength", "maxLength", "minItems", "maxItems", "pattern"}
+	if d.version >= 4 {
+		keywords = append(keywords, "minProperties", "maxProperties", "multipleOf")
+	}
+	if d.version >= 6 {
+		keywords = append(keywords, "const", "exclusiveMinimum", "exclusiveMaximum")
+	}
+	return keywords
+}
+
+// dataRef returns the json-pointer, if v is "$data" reference
+// i.e. an object with only "$data" string property.
+func dataRef(v interface{}) (string, bool) {
+	m, ok := v.(*OrderedMap)
+	if !ok || len(m.Keys()) != 1 {
+		return "", false
+	}
+	ptr, ok := m.RawValues()["$data"].(string)
+	return ptr, ok
+}
+
+// stripData returns copy of v, without the keywords using "$data" references.
+// This is used to validate schema against meta-schema, when Compiler.Data is true.
+//
+// Before draft6, boolean "exclusiveMinimum" is stripped along with "minimum",
+// because it requires "minimum". Similarly for "exclusiveMaximum".
+func stripData(d *Draft, v interface{}) interface{} {
+	switch v := v.(type) {
+	case *OrderedMap:
+		keywords := dataKeywords(d)
+		isData := func(kw string) bool {
+			kv, _ := v.Get(kw)
+			_, ok := dataRef(kv)
+			return ok && contains(keywords, kw)
+		}
+		m := NewOrderedMap()
+		for _, k := range v.Keys() {
+			kv, _ := v.Get(k)
+			if isData(k) {
+				continue
+			}
+			if _, ok := kv.(bool); ok && d.version < 6 {
+				if (k == "exclusiveMinimum" && isData("minimum")) || (k == "exclusiveMaximum" && isData("maximum")) {
+					continue
+				}
+			}
+			m.Set(k, stripData(d, kv))
+		}
+		return m
+	case []interface{}:
+		arr := make([]interface{}, len(v))
+		for i, item := range v {
+			arr[i] = stripData(d, item)
+		}
+		return arr
+	}
+	return v
+}
+
+// compileData returns the "$data" references of keywords in m, and
+// m without those keywords.
+func compileData(r *resource, res *resource, m *OrderedMap) (*OrderedMap, map[string]string, error) {
+	var data map[string]string
+	for _, kw := range dataKeywords(r.draft) {
+		v, _ := m.Get(kw)
+		ptr, ok := dataRef(v)
+		if !ok {
+			continue
+		}
+		if !isJSONPointer(ptr) && !isRelativeJSONPointer(ptr) {
+			return nil, nil, fmt.Errorf("jsonschema: invalid $data %s for %s in %s", quote(ptr), kw, r.url+res.floc)
+		}
+		if data == nil {
+			data = make(map[string]string)
+		}
+		data[kw] = ptr
+	}
+	if data == nil {
+		return m, nil, nil
+	}
+	fm := NewOrderedMap()
+	for _, kw := range m.Keys() {
+		if _, ok := data[kw]; !ok {
+			v, _ := m.Get(kw)
+			fm.Set(kw, v)
+		}
+	}
+	return fm, data, nil
+}
+
+// resolveData returns copy of s, with the keywords set to the values
+// referred by their "$data" references. The references are resolved in doc,
+// relative to instance location vloc. The keywords whose references are
+// not found in doc, are ignored.
+func (s *Schema) resolveData(doc interface{}, vloc string) (*Schema, *DataError) {
+	sch := *s
+	for _, kw := range sortedKeys(s.Data) {
+		ptr := s.Data[kw]
+		v, ok := dataValue(doc, vloc, ptr)
+		if !ok {
+			continue
+		}
+		invalid := func(want string) *DataError {
+			return &DataError{Pointer: ptr, Want: want, Got: v, keyword: kw}
+		}
+		switch kw {
+		case "const":
+			sch.Constant = []interface{}{v}
+		case "enum":
+			arr, ok := v.([]interface{})
+			if !ok {
+				return nil, invalid("array")
+			}
+			sch.Enum = arr
+		case "format":
+			format, ok := v.(string)
+			if !ok {
+				return nil, invalid("string")
+			}
+			sch.Format, sch.format = format, Formats[format]
+		case "pattern":
+			str, ok := v.(string)
+			if !ok {
+				return nil, invalid("regex")
+			}
+			re, err := regexp.Compile(str)
+			if err != nil {
+				return nil, invalid("regex")
+			}
+			sch.Pattern = re
+		case "minLength", "maxLength", "minItems", "maxItems", "minProperties", "maxProperties":
+			num := dataRat(v)
+			if num == nil || !num.IsInt() || num.Sign() < 0 || !num.Num().IsInt64() {
+				return nil, invalid("non-negative integer")
+			}
+			n := int(num.Num().Int64())
+			switch kw {
+			case "minLength":
+				sch.MinLength = n
+			case "maxLength":
+				sch.MaxLength = n
+			case "minItems":
+				sch.MinItems = n
+			case "maxItems":
+				sch.MaxItems = n
+			case "minProperties":
+				sch.MinProperties = n
+			case "maxProperties":
+				sch.MaxProperties = n
+			}
+		default:
+			num := dataRat(v)
+			if num == nil || (kw == "multipleOf" && num.Sign() <= 0) {
+				return nil, invalid("number")
+			}
+			switch kw {
+			case "minimum":
+				if s.dataExclusiveMinimum {
+					sch.ExclusiveMinimum = num
+				} else {
+					sch.Minimum = num
+				}
+			case "maximum":
+				if s.dataExclusiveMaximum {
+					sch.ExclusiveMaximum = num
+				} else {
+					sch.Maximum = num
+				}
+			case "exclusiveMinimum":
+				sch.ExclusiveMinimum = num
+			case "exclusiveMaximum":
+				sch.ExclusiveMaximum = num
+			case "multipleOf":
+				sch.MultipleOf = num
+			}
+		}
+	}
+	return &sch, nil
+}
+
+// dataValue returns the value referred by ptr in doc. ptr is either
+// json-pointer or relative-json-pointer, which is resolved relative to vloc.
+func dataValue(doc interface{}, vloc, ptr string) (interface{}, bool) {
+	get := func(tokens []string) (interface{}, bool) {
+		v := doc
+		for _, tok := range tokens {
+			cv, ok := child(v, tok)
+			if !ok {
+				return nil, false
+			}
+			v = cv
+		}
+		return v, true
+	}
+	var tokens []string
+	if ptr != "" && ptr[0] != '/' {
+		// relative-json-pointer
+		if vloc != "" {
+			for _, tok := range strings.Split(vloc[1:], "/") {
+				tokens = append(tokens, unescape(tok))
+			}
+		}
+		i := 0
+		for i < len(ptr) && ptr[i] >= '0' && ptr[i] <= '9' {
+			i++
+		}
+		up, err := strconv.Atoi(ptr[:i])
+		if err != nil || up > len(tokens) {
+			return nil, false
+		}
+		tokens, ptr = tokens[:len(tokens)-up], ptr[i:]
+		if ptr == "#" {
+			if len(tokens) == 0 {
+				return nil, false
+			}
+			key := tokens[len(tokens)-1]
+			parent, _ := get(tokens[:len(tokens)-1])
+			if _, ok := parent.([]interface{}); ok {
+				index, _ := strconv.Atoi(key)
+				return index, true
+			}
+			return key, true
+		}
+	}
+	rest, err := parsePtr(ptr)
+	if err != nil {
+		return nil, false
+	}
+	return get(append(tokens, rest...))
+}
+
+// dataRat returns v as *big.Rat. returns nil if v is not number.
+func dataRat(v interface{}) *big.Rat {
+	switch v.(type) {
+	case json.Number, float64, int, int32, int64:
+		r, _ := new(big.Rat).SetString(fmt.Sprint(v))
+		return r
+	}
+	return nil
+}
+
+func contains(arr []string, s string) bool {
+	for _, item := range arr {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
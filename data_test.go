@@ -0,0 +1,193 @@
+package jsonschema_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+func TestData(t *testing.T) {
+	tests := []struct {
+		description string
+		schema      string
+		doc         string
+		valid       bool
+	}{
+		{
+			description: "maximum relative to parent",
+			schema:      `{"properties": {"price": {"maximum": {"$data": "1/maxPrice"}}}}`,
+			doc:         `{"price": 10, "maxPrice": 20}`,
+			valid:       true,
+		},
+		{
+			description: "maximum relative to parent, invalid",
+			schema:      `{"properties": {"price": {"maximum": {"$data": "1/maxPrice"}}}}`,
+			doc:         `{"price": 30, "maxPrice": 20}`,
+		},
+		{
+			description: "missing reference is ignored",
+			schema:      `{"properties": {"price": {"maximum": {"$data": "1/maxPrice"}}}}`,
+			doc:         `{"price": 30}`,
+			valid:       true,
+		},
+		{
+			description: "end date after start date",
+			schema:      `{"properties": {"end": {"exclusiveMinimum": {"$data": "/start"}}}}`,
+			doc:         `{"start": 5, "end": 5}`,
+		},
+		{
+			description: "const",
+			schema:      `{"properties": {"confirm": {"const": {"$data": "1/password"}}}}`,
+			doc:         `{"password": "secret", "confirm": "secret"}`,
+			valid:       true,
+		},
+		{
+			description: "const, invalid",
+			schema:      `{"properties": {"confirm": {"const": {"$data": "1/password"}}}}`,
+			doc:         `{"password": "secret", "confirm": "public"}`,
+		},
+		{
+			description: "enum",
+			schema:      `{"properties": {"color": {"enum": {"$data": "/colors"}}}}`,
+			doc:         `{"colors": ["red", "green"], "color": "blue"}`,
+		},
+		{
+			description: "minLength",
+			schema:      `{"items": {"properties": {"name": {"minLength": {"$data": "2/0/min"}}}}}`,
+			doc:         `[{"min": 3, "name": "ab"}]`,
+		},
+		{
+			description: "pattern",
+			schema:      `{"properties": {"code": {"pattern": {"$data": "1/codePattern"}}}}`,
+			doc:         `{"codePattern": "^[A-Z]+$", "code": "ABC"}`,
+			valid:       true,
+		},
+		{
+			description: "format",
+			schema:      `{"properties": {"value": {"format": {"$data": "1/kind"}}}}`,
+			doc:         `{"kind": "email", "value": "not an email"}`,
+		},
+		{
+			description: "key of parent",
+			schema:      `{"additionalProperties": {"properties": {"name": {"const": {"$data": "1#"}}}}}`,
+			doc:         `{"a": {"name": "a"}, "b": {"name": "b"}}`,
+			valid:       true,
+		},
+		{
+			description: "referenced value of invalid type",
+			schema:      `{"properties": {"price": {"maximum": {"$data": "1/maxPrice"}}}}`,
+			doc:         `{"price": 10, "maxPrice": "20"}`,
+		},
+		{
+			description: "draft4 minimum with exclusiveMinimum",
+			schema:      `{"$schema": "http://json-schema.org/draft-04/schema#", "properties": {"x": {"minimum": {"$data": "1/lim"}, "exclusiveMinimum": true}}}`,
+			doc:         `{"lim": 5, "x": 5}`,
+		},
+		{
+			description: "draft4 minimum with exclusiveMinimum, valid",
+			schema:      `{"$schema": "http://json-schema.org/draft-04/schema#", "properties": {"x": {"minimum": {"$data": "1/lim"}, "exclusiveMinimum": true}}}`,
+			doc:         `{"lim": 5, "x": 6}`,
+			valid:       true,
+		},
+		{
+			description: "draft4 maximum with exclusiveMaximum",
+			schema:      `{"$schema": "http://json-schema.org/draft-04/schema#", "properties": {"x": {"maximum": {"$data": "1/lim"}, "exclusiveMaximum": true}}}`,
+			doc:         `{"lim": 5, "x": 5}`,
+		},
+		{
+			description: "draft4 minimum with exclusiveMinimum false",
+			schema:      `{"$schema": "http://json-schema.org/draft-04/schema#", "properties": {"x": {"minimum": {"$data": "1/lim"}, "exclusiveMinimum": false}}}`,
+			doc:         `{"lim": 5, "x": 5}`,
+			valid:       true,
+		},
+		{
+			description: "invalid regex",
+			schema:      `{"properties": {"code": {"pattern": {"$data": "1/codePattern"}}}}`,
+			doc:         `{"codePattern": "(", "code": "ABC"}`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			c := jsonschema.NewCompiler()
+			c.Data = true
+			c.AssertFormat = true
+			if err := c.AddResource("schema.json", strings.NewReader(test.schema)); err != nil {
+				t.Fatal(err)
+			}
+			sch, err := c.Compile("schema.json")
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = sch.Validate(decodeString(t, test.doc))
+			if test.valid && err != nil {
+				t.Fatal(err)
+			}
+			if !test.valid && err == nil {
+				t.Fatal("want validation error")
+			}
+		})
+	}
+}
+
+func TestData_Error(t *testing.T) {
+	c := jsonschema.NewCompiler()
+	c.Data = true
+	if err := c.AddResource("schema.json", strings.NewReader(`{"properties": {"price": {"maximum": {"$data": "1/maxPrice"}}}}`)); err != nil {
+		t.Fatal(err)
+	}
+	sch := c.MustCompile("schema.json")
+	ve, ok := sch.Validate(decodeString(t, `{"price": 10, "maxPrice": "20"}`)).(*jsonschema.ValidationError)
+	if !ok {
+		t.Fatal("want *ValidationError")
+	}
+	ve = ve.BestMatch()
+	if ve.KeywordLocation != "/properties/price/maximum" {
+		t.Errorf("keyword location: got %q", ve.KeywordLocation)
+	}
+	kind, ok := ve.Kind.(*jsonschema.DataError)
+	if !ok {
+		t.Fatalf("want *DataError, got %#v", ve.Kind)
+	}
+	if kind.Keyword() != "maximum" || kind.Want != "number" || kind.Got != "20" {
+		t.Errorf("unexpected %#v", kind)
+	}
+	if want := `$data '1/maxPrice' must be number, but got 20`; ve.Message != want {
+		t.Errorf("message: got %q, want %q", ve.Message, want)
+	}
+}
+
+func TestData_Compile(t *testing.T) {
+	tests := []struct {
+		description string
+		schema      string
+		data        bool
+		valid       bool
+	}{
+		{"disabled", `{"maximum": {"$data": "1/max"}}`, false, false},
+		{"enabled", `{"maximum": {"$data": "1/max"}}`, true, true},
+		{"not allowed keyword", `{"required": {"$data": "1/props"}}`, true, false},
+		{"exclusiveMinimum with minimum in draft4", `{"$schema": "http://json-schema.org/draft-04/schema#", "minimum": {"$data": "1/min"}, "exclusiveMinimum": true}`, true, true},
+		{"exclusiveMinimum without minimum in draft4", `{"$schema": "http://json-schema.org/draft-04/schema#", "exclusiveMinimum": true}`, true, false},
+		{"not allowed in draft4", `{"$schema": "http://json-schema.org/draft-04/schema#", "exclusiveMinimum": {"$data": "1/min"}}`, true, false},
+		{"invalid pointer", `{"maximum": {"$data": "a/max"}}`, true, false},
+		{"non-string pointer", `{"maximum": {"$data": 1}}`, true, false},
+		{"extra properties", `{"maximum": {"$data": "1/max", "x": 1}}`, true, false},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			c := jsonschema.NewCompiler()
+			c.Data = test.data
+			if err := c.AddResource("schema.json", strings.NewReader(test.schema)); err != nil {
+				t.Fatal(err)
+			}
+			_, err := c.Compile("schema.json")
+			if test.valid && err != nil {
+				t.Fatal(err)
+			}
+			if !test.valid && err == nil {
+				t.Fatal("want compilation error")
+			}
+		})
+	}
+}
@@ -0,0 +1,369 @@
+package jsonschema
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// RetainedRef describes a reference which is not inlined by Dereference.
+type RetainedRef struct {
+	Location string // json-pointer to the reference in dereferenced document
+	Ref      string // absolute location of the referenced schema
+	Reason   string // why it is not inlined
+}
+
+// Dereference returns standalone document for the schema at given url,
+// where references are replaced with the schemas they refer to.
+//
+// In draft2019-09 and later, keywords next to "$ref" are retained and the
+// referenced schema is added to "allOf". "$recursiveRef" and "$dynamicRef" are
+// resolved using the dynamic scope at their location in the returned document.
+//
+// Recursive references cannot be inlined. Such schemas are added to "$defs"
+// ("definitions" before draft2019-09) of the returned document and referred
+// using local "$ref". References to meta-schemas are retained as is. All the
+// references that are not inlined are reported as []*RetainedRef.
+//
+// The returned value is either bool or *OrderedMap.
+func (c *Compiler) Dereference(url string) (interface{}, []*RetainedRef, error) {
+	if _, err := c.Compile(url); err != nil {
+		return nil, nil, err
+	}
+	u, err := toAbs(url)
+	if err != nil {
+		return nil, nil, err
+	}
+	d := &dereferencer{c: c, names: make(map[string]string)}
+	start, err := d.resolve(u)
+	if err != nil {
+		return nil, nil, err
+	}
+	d.start = start
+
+	doc, err := d.inline(start, "#", nil, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// render recursive schemas in $defs
+	defsKey := "$defs"
+	if start.r.draft.version < 2019 {
+		defsKey = "definitions"
+	}
+	defs := NewOrderedMap()
+	for i := 0; i < len(d.pending); i++ {
+		t := d.pending[i]
+		name := d.names[t.key()]
+		def, err := d.inline(t, "#/"+defsKey+"/"+escape(name), []string{t.key()}, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		defs.Set(name, def)
+	}
+
+	m, ok := doc.(*OrderedMap)
+	if !ok {
+		return doc, d.retained, nil
+	}
+	result := NewOrderedMap()
+	if sch, ok := start.r.doc.(*OrderedMap); ok {
+		if v, ok := sch.Get("$schema"); ok {
+			result.Set("$schema", v)
+		}
+	}
+	for _, k := range m.Keys() {
+		v, _ := m.Get(k)
+		result.Set(k, v)
+	}
+	if len(defs.Keys()) > 0 {
+		result.Set(defsKey, defs)
+	}
+	return result, d.retained, nil
+}
+
+// derefTarget identifies schema at floc in root resource r.
+type derefTarget struct {
+	r    *resource
+	floc string
+}
+
+func (t derefTarget) key() string {
+	return t.r.url + t.floc
+}
+
+func (t derefTarget) doc() interface{} {
+	if t.floc == t.r.floc {
+		return t.r.doc
+	}
+	return t.r.subresources[t.floc].doc
+}
+
+type dereferencer struct {
+	c        *Compiler
+	start    derefTarget
+	names    map[string]string // key of recursive target to its name in $defs
+	pending  []derefTarget     // recursive targets to be rendered in $defs
+	retained []*RetainedRef
+}
+
+// resolve returns the target, identified by absolute url.
+func (d *dereferencer) resolve(url string) (derefTarget, error) {
+	u, f := split(url)
+	r, err := d.c.findResource(u)
+	if err != nil {
+		return derefTarget{}, err
+	}
+	return d.resolveIn(r, r, f)
+}
+
+// resolveIn resolves fragment f, with base sr in root resource r.
+func (d *dereferencer) resolveIn(r, sr *resource, f string) (derefTarget, error) {
+	res, err := r.resolveFragment(d.c, sr, f)
+	if err != nil {
+		return derefTarget{}, err
+	}
+	if res == nil {
+		return derefTarget{}, fmt.Errorf("jsonschema: %s not found", sr.url+f)
+	}
+	return derefTarget{r, res.floc}, nil
+}
+
+// resolveRef resolves ref at floc in root resource r.
+func (d *dereferencer) resolveRef(r *resource, floc, ref string) (derefTarget, string, error) {
+	abs, err := resolveURL(r.baseURL(floc), ref)
+	if err != nil {
+		return derefTarget{}, "", err
+	}
+	u, f := split(abs)
+	if findDraft(u) != nil {
+		return derefTarget{}, abs, nil
+	}
+	if sr := r.findResource(u); sr != nil {
+		t, err := d.resolveIn(r, sr, f)
+		return t, abs, err
+	}
+	t, err := d.resolve(abs)
+	return t, abs, err
+}
+
+// dynamicTarget returns the target of "$recursiveRef" or "$dynamicRef",
+// whose static target is t, based on given dynamic scope.
+func (d *dereferencer) dynamicTarget(kw string, t derefTarget, scope []derefTarget) (derefTarget, error) {
+	m, ok := t.doc().(*OrderedMap)
+	if !ok {
+		return t, nil
+	}
+	switch kw {
+	case "$recursiveRef":
+		if v, _ := m.Get("$recursiveAnchor"); v != true {
+			return t, nil
+		}
+		for _, st := range scope {
+			if sm, ok := st.doc().(*OrderedMap); ok {
+				if v, _ := sm.Get("$recursiveAnchor"); v == true {
+					return st, nil
+				}
+			}
+		}
+	case "$dynamicRef":
+		anchor, ok := m.Get("$dynamicAnchor")
+		if !ok {
+			return t, nil
+		}
+		for _, st := range scope {
+			res := st.r
+			if st.floc != st.r.floc {
+				res = st.r.subresources[st.floc]
+			}
+			at, err := d.resolveIn(st.r, res, "#"+anchor.(string))
+			if err != nil {
+				continue
+			}
+			if am, ok := at.doc().(*OrderedMap); ok {
+				if v, _ := am.Get("$dynamicAnchor"); v == anchor {
+					return at, nil
+				}
+			}
+		}
+	}
+	return t, nil
+}
+
+// inline returns copy of the schema t, with references inlined.
+//
+// loc is json-pointer of the schema in the returned document. stack
+// contains keys of the schemas being inlined.
+func (d *dereferencer) inline(t derefTarget, loc string, stack []string, scope []derefTarget) (interface{}, error) {
+	stack = append(stack, t.key())
+	return d.copy(t.r, t.floc, t.doc(), loc, stack, scope)
+}
+
+func (d *dereferencer) copy(r *resource, floc string, v interface{}, loc string, stack []string, scope []derefTarget) (interface{}, error) {
+	switch v := v.(type) {
+	case *OrderedMap:
+		if _, ok := r.subresources[floc]; ok || floc == r.floc {
+			return d.copySchema(r, floc, v, loc, stack, scope)
+		}
+		m := NewOrderedMap()
+		for _, k := range v.Keys() {
+			kv, _ := v.Get(k)
+			kv, err := d.copy(r, floc+"/"+escape(k), kv, loc+"/"+escape(k), stack, scope)
+			if err != nil {
+				return nil, err
+			}
+			m.Set(k, kv)
+		}
+		return m, nil
+	case []interface{}:
+		arr := make([]interface{}, len(v))
+		for i, item := range v {
+			item, err := d.copy(r, floc+"/"+strconv.Itoa(i), item, loc+"/"+strconv.Itoa(i), stack, scope)
+			if err != nil {
+				return nil, err
+			}
+			arr[i] = item
+		}
+		return arr, nil
+	default:
+		return v, nil
+	}
+}
+
+func (d *dereferencer) copySchema(r *resource, floc string, v *OrderedMap, loc string, stack []string, scope []derefTarget) (interface{}, error) {
+	draft := r.draft
+
+	// entering new schema resource, extends dynamic scope
+	base := r.baseURL(floc)
+	if n := len(scope); n == 0 || scope[n-1].r.baseURL(scope[n-1].floc) != base {
+		if res := r.findResource(base); res != nil {
+			scope = append(scope, derefTarget{r, res.floc})
+		}
+	}
+
+	isRef := func(kw string) bool {
+		for _, rkw := range refKeywords(draft) {
+			if kw == rkw {
+				return true
+			}
+		}
+		return false
+	}
+	skip := func(kw string) bool {
+		switch kw {
+		case draft.id, "$schema", "$anchor", "$dynamicAnchor", "$recursiveAnchor", "$defs", "definitions":
+			return true
+		}
+		return false
+	}
+
+	// compute keywords to be retained
+	var refs, keywords []string
+	for _, kw := range v.Keys() {
+		switch {
+		case isRef(kw):
+			if _, ok := v.Get(kw); ok {
+				refs = append(refs, kw)
+			}
+		case skip(kw):
+		default:
+			keywords = append(keywords, kw)
+		}
+	}
+	if len(refs) > 0 && draft.version < 2019 {
+		// All other properties in a "$ref" object MUST be ignored
+		keywords = nil
+	}
+
+	m := NewOrderedMap()
+	for _, kw := range keywords {
+		kv, _ := v.Get(kw)
+		kv, err := d.copy(r, floc+"/"+escape(kw), kv, loc+"/"+escape(kw), stack, scope)
+		if err != nil {
+			return nil, err
+		}
+		m.Set(kw, kv)
+	}
+	if len(refs) == 0 {
+		return m, nil
+	}
+
+	var allOf []interface{}
+	if v, ok := m.Get("allOf"); ok {
+		allOf = v.([]interface{})
+	}
+	replace := len(keywords) == 0 && len(refs) == 1
+	for _, kw := range refs {
+		ref, _ := v.Get(kw)
+		refLoc := loc
+		if !replace {
+			refLoc = loc + "/allOf/" + strconv.Itoa(len(allOf))
+		}
+		inlined, err := d.inlineRef(r, floc, kw, ref.(string), refLoc, stack, scope)
+		if err != nil {
+			return nil, err
+		}
+		if replace {
+			return inlined, nil
+		}
+		allOf = append(allOf, inlined)
+	}
+	m.Set("allOf", allOf)
+	return m, nil
+}
+
+// inlineRef returns the schema to be used in place of reference kw with value ref.
+func (d *dereferencer) inlineRef(r *resource, floc, kw, ref, loc string, stack []string, scope []derefTarget) (interface{}, error) {
+	t, abs, err := d.resolveRef(r, floc, ref)
+	if err != nil {
+		return nil, err
+	}
+	localRef := func(ref, reason string) interface{} {
+		d.retained = append(d.retained, &RetainedRef{Location: loc, Ref: abs, Reason: reason})
+		m := NewOrderedMap()
+		m.Set("$ref", ref)
+		return m
+	}
+	if t.r == nil {
+		return localRef(abs, "meta-schema"), nil
+	}
+	if kw != "$ref" {
+		if t, err = d.dynamicTarget(kw, t, scope); err != nil {
+			return nil, err
+		}
+		abs = t.key()
+	}
+
+	key := t.key()
+	for _, k := range stack {
+		if k != key {
+			continue
+		}
+		if key == d.start.key() {
+			return localRef("#", "recursive"), nil
+		}
+		name, ok := d.names[key]
+		if !ok {
+			name = d.defName(t)
+			d.names[key] = name
+			d.pending = append(d.pending, t)
+		}
+		defsKey := "$defs"
+		if d.start.r.draft.version < 2019 {
+			defsKey = "definitions"
+		}
+		return localRef("#/"+defsKey+"/"+escape(name), "recursive"), nil
+	}
+	return d.inline(t, loc, stack, scope)
+}
+
+// defName returns unique name for t, to be used in $defs.
+func (d *dereferencer) defName(t derefTarget) string {
+	names := NewOrderedMap()
+	for _, name := range d.names {
+		names.Set(name, true)
+	}
+	if t.floc == "#" {
+		return defName(names, t.r.url)
+	}
+	return defName(names, t.floc[1:])
+}
@@ -0,0 +1,138 @@
+package jsonschema_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+func TestCompiler_Dereference(t *testing.T) {
+	tests := []struct {
+		description string
+		draft       *jsonschema.Draft
+		schemas     map[string]string
+		retained    []string // locations of retained refs
+		instances   []string
+	}{
+		{
+			description: "non recursive",
+			draft:       jsonschema.Draft2020,
+			schemas: map[string]string{
+				"schema.json": `{
+					"properties": {
+						"a": {"$ref": "#/$defs/positive"},
+						"b": {"$ref": "other.json#/$defs/name", "maxLength": 3}
+					},
+					"$defs": {"positive": {"type": "integer", "minimum": 1}}
+				}`,
+				"other.json": `{"$defs": {"name": {"type": "string", "minLength": 1}}}`,
+			},
+			instances: []string{`{"a": 1, "b": "x"}`, `{"a": 0}`, `{"b": ""}`, `{"b": "long"}`},
+		},
+		{
+			description: "ref siblings ignored",
+			draft:       jsonschema.Draft7,
+			schemas: map[string]string{
+				"schema.json": `{
+					"definitions": {"str": {"type": "string"}},
+					"items": {"$ref": "#/definitions/str", "type": "integer"}
+				}`,
+			},
+			instances: []string{`["a"]`, `[1]`},
+		},
+		{
+			description: "recursive",
+			draft:       jsonschema.Draft2020,
+			schemas: map[string]string{
+				"schema.json": `{
+					"properties": {"root": {"$ref": "tree.json"}, "self": {"$ref": "#"}},
+					"$defs": {"leaf": {"type": "integer"}}
+				}`,
+				"tree.json": `{
+					"type": "object",
+					"properties": {
+						"value": {"$ref": "schema.json#/$defs/leaf"},
+						"children": {"type": "array", "items": {"$ref": "#"}}
+					}
+				}`,
+			},
+			retained:  []string{"#/properties/root/properties/children/items", "#/properties/self", "#/$defs/tree/properties/children/items"},
+			instances: []string{`{"root": {"value": 1, "children": [{"value": 2}]}}`, `{"root": {"children": [{"value": "x"}]}}`, `{"self": {"root": {"value": "x"}}}`},
+		},
+		{
+			description: "recursiveRef",
+			draft:       jsonschema.Draft2019,
+			schemas: map[string]string{
+				"schema.json": `{
+					"$recursiveAnchor": true,
+					"$ref": "tree.json",
+					"properties": {"value": {"type": "integer"}}
+				}`,
+				"tree.json": `{
+					"$recursiveAnchor": true,
+					"properties": {"children": {"type": "array", "items": {"$recursiveRef": "#"}}}
+				}`,
+			},
+			retained:  []string{"#/allOf/0/properties/children/items"},
+			instances: []string{`{"children": [{"value": 1}]}`, `{"children": [{"value": "x"}]}`},
+		},
+		{
+			description: "meta-schema",
+			draft:       jsonschema.Draft2020,
+			schemas: map[string]string{
+				"schema.json": `{"properties": {"schema": {"$ref": "https://json-schema.org/draft/2020-12/schema"}}}`,
+			},
+			retained:  []string{"#/properties/schema"},
+			instances: []string{`{"schema": {"type": "string"}}`, `{"schema": {"type": 1}}`},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			c := jsonschema.NewCompiler()
+			c.Draft = test.draft
+			for url, schema := range test.schemas {
+				if err := c.AddResource(url, strings.NewReader(schema)); err != nil {
+					t.Fatal(err)
+				}
+			}
+			sch, err := c.Compile("schema.json")
+			if err != nil {
+				t.Fatal(err)
+			}
+			doc, retained, err := c.Dereference("schema.json")
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, _ := json.Marshal(doc)
+			t.Logf("%s", b)
+
+			var locs []string
+			for _, r := range retained {
+				locs = append(locs, r.Location)
+			}
+			if strings.Join(locs, " ") != strings.Join(test.retained, " ") {
+				t.Errorf("retained: got %v, want %v", locs, test.retained)
+			}
+
+			dc := jsonschema.NewCompiler()
+			dc.Draft = test.draft
+			if err := dc.AddResource("deref.json", bytes.NewReader(b)); err != nil {
+				t.Fatal(err)
+			}
+			dsch, err := dc.Compile("deref.json")
+			if err != nil {
+				t.Fatalf("%#v", err)
+			}
+			for _, instance := range test.instances {
+				want := sch.Validate(decodeString(t, instance)) == nil
+				got := dsch.Validate(decodeString(t, instance)) == nil
+				if got != want {
+					t.Errorf("%s: got %v, want %v", instance, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package jsonschema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RegisterDialect registers a dialect, identified by "$schema" uri.
+//
+// Schemas whose "$schema" is uri are compiled as per base draft, with only the
+// extensions named in exts enabled. meta is used to validate such schemas,
+// in addition to the metaschemas of enabled extensions. If meta is nil, the
+// metaschema of base is used.
+//
+// uri is resolved like "$schema" values. An error is returned if uri
+// is invalid, or it is the uri of a supported draft.
+func (c *Compiler) RegisterDialect(uri string, base *Draft, meta *Schema, exts ...string) error {
+	if findDraft(uri) != nil {
+		return fmt.Errorf("jsonschema: cannot register dialect %s, it is uri of supported draft", uri)
+	}
+	u, err := toAbs(uri)
+	if err != nil {
+		return err
+	}
+	u, _ = split(u)
+	d := *base
+	d.url = u
+	if meta != nil {
+		d.meta = meta
+	}
+	d.exts = append([]string{}, exts...)
+	c.dialects[u] = &d
+	return nil
+}
+
+// findDialect returns the draft for given "$schema" url.
+//
+// If url is not one of the supported drafts, the meta-schema at url is
+// loaded and a dialect is derived from the draft of that meta-schema.
+// In draft2019-09 and later, "$vocabulary" of the meta-schema decides
+// which vocabularies are enabled in the dialect. Schemas using the
+// dialect are validated against that meta-schema.
+func (c *Compiler) findDialect(url string) (*Draft, error) {
+	if d := findDraft(url); d != nil {
+		return d, nil
+	}
+	u, err := toAbs(url)
+	if err != nil {
+		return nil, err
+	}
+	u, _ = split(u)
+	if d, ok := c.dialects[u]; ok {
+		if d == nil {
+			return nil, fmt.Errorf("jsonschema: meta-schema %s uses itself as $schema", u)
+		}
+		return d, nil
+	}
+
+	c.dialects[u] = nil // to detect cycle
+	d, err := c.loadDialect(u)
+	if err != nil {
+		delete(c.dialects, u)
+		return nil, err
+	}
+	c.dialects[u] = d
+	return d, nil
+}
+
+func (c *Compiler) loadDialect(url string) (*Draft, error) {
+	r, err := c.findResource(url)
+	if err != nil {
+		return nil, err
+	}
+	meta, err := c.compileURL(url, nil, "#")
+	if err != nil {
+		return nil, err
+	}
+
+	base := r.draft
+	d := *base
+	d.url = url
+	d.meta = meta
+	if base.version < 2019 {
+		return &d, nil
+	}
+	m, ok := r.doc.(*OrderedMap)
+	if !ok {
+		return &d, nil
+	}
+	v, ok := m.Get("$vocabulary")
+	if !ok {
+		return &d, nil
+	}
+	vocabs, ok := v.(*OrderedMap)
+	if !ok {
+		return nil, fmt.Errorf("jsonschema: invalid $vocabulary in %s", url)
+	}
+	d.vocab = []string{}
+	for _, uri := range vocabs.Keys() {
+		if name := strings.TrimPrefix(uri, base.vocabPrefix); name != uri {
+			if _, ok := base.vocabularies[name]; ok {
+				d.vocab = append(d.vocab, name)
+				continue
+			}
+		}
+		if _, ok := c.extensions[uri]; ok {
+			d.vocab = append(d.vocab, uri)
+			continue
+		}
+		if required, _ := vocabs.Get(uri); required == true {
+			return nil, fmt.Errorf("jsonschema: unsupported vocabulary %s in %s", uri, url)
+		}
+	}
+	return &d, nil
+}
+
+// hasVocab tells whether vocabulary with given name is explicitly
+// enabled in this dialect. name is either uri or, for standard
+// vocabularies, the last path segment of uri.
+func (d *Draft) hasVocab(name string) bool {
+	for _, v := range d.vocab {
+		if v == name {
+			return true
+		}
+	}
+	return false
+}
+
+// isExtEnabled tells whether extension with given name is enabled.
+// In dialects registered with Compiler.RegisterDialect, only the listed
+// extensions are enabled. Otherwise extensions named by vocabulary uri
+// are enabled only if the dialect lists that vocabulary.
+func (d *Draft) isExtEnabled(name string) bool {
+	if d.exts != nil {
+		for _, ext := range d.exts {
+			if ext == name {
+				return true
+			}
+		}
+		return false
+	}
+	if d.vocab == nil || !strings.Contains(name, "://") {
+		return true
+	}
+	return d.hasVocab(name)
+}
+
+// filterKeywords returns copy of m, without the keywords belonging to
+// vocabularies which are not enabled.
+func (d *Draft) filterKeywords(m *OrderedMap) *OrderedMap {
+	if d.vocab == nil {
+		return m
+	}
+	enabled := func(kw string) bool {
+		found := false
+		for name, keywords := range d.vocabularies {
+			for _, k := range keywords {
+				if k == kw {
+					if d.hasVocab(name) {
+						return true
+					}
+					found = true
+				}
+			}
+		}
+		return !found
+	}
+	fm := NewOrderedMap()
+	for _, kw := range m.Keys() {
+		if enabled(kw) {
+			v, _ := m.Get(kw)
+			fm.Set(kw, v)
+		}
+	}
+	return fm
+}
@@ -0,0 +1,285 @@
+package jsonschema_test
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+const powerOfVocab = "https://example.com/vocab/powerOf"
+
+func dialectCompiler(t *testing.T, metas map[string]string) *jsonschema.Compiler {
+	t.Helper()
+	c := jsonschema.NewCompiler()
+	c.RegisterExtension(powerOfVocab, powerOfMeta, powerOfCompiler{})
+	for url, meta := range metas {
+		if err := c.AddResource(url, strings.NewReader(meta)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return c
+}
+
+func TestDialect(t *testing.T) {
+	metas := map[string]string{
+		"https://example.com/full": `{
+			"$schema": "https://json-schema.org/draft/2020-12/schema",
+			"$id": "https://example.com/full",
+			"$vocabulary": {
+				"https://json-schema.org/draft/2020-12/vocab/core": true,
+				"https://json-schema.org/draft/2020-12/vocab/applicator": true,
+				"https://json-schema.org/draft/2020-12/vocab/validation": true,
+				"https://json-schema.org/draft/2020-12/vocab/format-assertion": true,
+				"https://example.com/vocab/powerOf": true
+			},
+			"$dynamicAnchor": "meta",
+			"allOf": [{"$ref": "https://json-schema.org/draft/2020-12/schema"}],
+			"properties": {
+				"powerOf": {"type": "integer", "exclusiveMinimum": 0}
+			}
+		}`,
+		"https://example.com/novalidation": `{
+			"$schema": "https://json-schema.org/draft/2020-12/schema",
+			"$id": "https://example.com/novalidation",
+			"$vocabulary": {
+				"https://json-schema.org/draft/2020-12/vocab/core": true,
+				"https://json-schema.org/draft/2020-12/vocab/applicator": true
+			},
+			"$dynamicAnchor": "meta",
+			"allOf": [{"$ref": "https://json-schema.org/draft/2020-12/schema"}]
+		}`,
+	}
+	tests := []struct {
+		description string
+		schema      string
+		valid       []string
+		invalid     []string
+	}{
+		{
+			description: "extension vocabulary enabled",
+			schema:      `{"$schema": "https://example.com/full", "powerOf": 10}`,
+			valid:       []string{`100`},
+			invalid:     []string{`111`},
+		},
+		{
+			description: "format-assertion vocabulary enabled",
+			schema:      `{"$schema": "https://example.com/full", "format": "ipv4"}`,
+			valid:       []string{`"1.2.3.4"`},
+			invalid:     []string{`"1.2.3"`},
+		},
+		{
+			description: "validation vocabulary disabled",
+			schema:      `{"$schema": "https://example.com/novalidation", "type": "string", "properties": {"a": {"minimum": 10}}}`,
+			valid:       []string{`1`, `{"a": 1}`},
+		},
+		{
+			description: "applicator vocabulary enabled",
+			schema:      `{"$schema": "https://example.com/novalidation", "properties": {"a": false}}`,
+			valid:       []string{`{}`},
+			invalid:     []string{`{"a": 1}`},
+		},
+		{
+			description: "extension vocabulary not listed",
+			schema:      `{"$schema": "https://example.com/novalidation", "powerOf": 10}`,
+			valid:       []string{`111`},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			c := dialectCompiler(t, metas)
+			if err := c.AddResource("schema.json", strings.NewReader(test.schema)); err != nil {
+				t.Fatal(err)
+			}
+			sch, err := c.Compile("schema.json")
+			if err != nil {
+				t.Fatalf("%#v", err)
+			}
+			for _, v := range test.valid {
+				if err := sch.Validate(decodeString(t, v)); err != nil {
+					t.Errorf("%s: %v", v, err)
+				}
+			}
+			for _, v := range test.invalid {
+				if err := sch.Validate(decodeString(t, v)); err == nil {
+					t.Errorf("%s: validation must fail", v)
+				}
+			}
+		})
+	}
+
+	t.Run("validated against meta-schema", func(t *testing.T) {
+		c := dialectCompiler(t, metas)
+		if err := c.AddResource("schema.json", strings.NewReader(`{"$schema": "https://example.com/full", "powerOf": -1}`)); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := c.Compile("schema.json"); err == nil {
+			t.Fatal("error expected")
+		} else {
+			t.Log(err)
+		}
+	})
+}
+
+func TestDialect_Invalid(t *testing.T) {
+	tests := []struct {
+		description string
+		metas       map[string]string
+	}{
+		{
+			description: "unsupported required vocabulary",
+			metas: map[string]string{
+				"https://example.com/meta": `{
+					"$schema": "https://json-schema.org/draft/2020-12/schema",
+					"$id": "https://example.com/meta",
+					"$vocabulary": {
+						"https://json-schema.org/draft/2020-12/vocab/core": true,
+						"https://example.com/vocab/unknown": true
+					}
+				}`,
+			},
+		},
+		{
+			description: "meta-schema uses itself as $schema",
+			metas: map[string]string{
+				"https://example.com/meta": `{
+					"$schema": "https://example.com/meta",
+					"$id": "https://example.com/meta"
+				}`,
+			},
+		},
+		{
+			description: "missing meta-schema",
+			metas:       map[string]string{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			c := dialectCompiler(t, test.metas)
+			if err := c.AddResource("schema.json", strings.NewReader(`{"$schema": "https://example.com/meta"}`)); err != nil {
+				t.Fatal(err)
+			}
+			c.LoadURL = func(s string) (io.ReadCloser, error) {
+				return nil, fmt.Errorf("%s not found", s)
+			}
+			if _, err := c.Compile("schema.json"); err == nil {
+				t.Fatal("error expected")
+			} else {
+				t.Log(err)
+			}
+		})
+	}
+}
+
+func TestRegisterDialect(t *testing.T) {
+	meta := jsonschema.MustCompileString("meta.json", `{
+		"properties": {
+			"powerOf": {"maximum": 100}
+		}
+	}`)
+	tests := []struct {
+		description string
+		schema      string
+		valid       []string
+		invalid     []string
+	}{
+		{
+			description: "extension enabled",
+			schema:      `{"$schema": "https://example.com/dialect", "powerOf": 10}`,
+			valid:       []string{`100`},
+			invalid:     []string{`111`},
+		},
+		{
+			description: "extension not listed",
+			schema:      `{"$schema": "https://example.com/noext", "powerOf": 10}`,
+			valid:       []string{`111`},
+		},
+		{
+			description: "base draft",
+			schema:      `{"$schema": "https://example.com/draft4", "minimum": 5, "exclusiveMinimum": true}`,
+			valid:       []string{`6`},
+			invalid:     []string{`5`},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			c := jsonschema.NewCompiler()
+			c.RegisterExtension("powerOf", powerOfMeta, powerOfCompiler{})
+			for _, err := range []error{
+				c.RegisterDialect("https://example.com/dialect", jsonschema.Draft2020, meta, "powerOf"),
+				c.RegisterDialect("https://example.com/noext", jsonschema.Draft2020, nil),
+				c.RegisterDialect("https://example.com/draft4", jsonschema.Draft4, nil),
+			} {
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := c.AddResource("schema.json", strings.NewReader(test.schema)); err != nil {
+				t.Fatal(err)
+			}
+			sch, err := c.Compile("schema.json")
+			if err != nil {
+				t.Fatalf("%#v", err)
+			}
+			for _, v := range test.valid {
+				if err := sch.Validate(decodeString(t, v)); err != nil {
+					t.Errorf("%s: %v", v, err)
+				}
+			}
+			for _, v := range test.invalid {
+				if err := sch.Validate(decodeString(t, v)); err == nil {
+					t.Errorf("%s: validation must fail", v)
+				}
+			}
+		})
+	}
+
+	t.Run("validated against meta", func(t *testing.T) {
+		c := jsonschema.NewCompiler()
+		c.RegisterExtension("powerOf", powerOfMeta, powerOfCompiler{})
+		if err := c.RegisterDialect("https://example.com/dialect", jsonschema.Draft2020, meta, "powerOf"); err != nil {
+			t.Fatal(err)
+		}
+		for _, schema := range []string{
+			`{"$schema": "https://example.com/dialect", "powerOf": 1000}`, // dialect meta
+			`{"$schema": "https://example.com/dialect", "powerOf": -1}`,   // extension meta
+		} {
+			if err := c.AddResource("schema.json", strings.NewReader(schema)); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := c.Compile("schema.json"); err == nil {
+				t.Fatalf("%s: error expected", schema)
+			} else {
+				t.Log(err)
+			}
+		}
+	})
+	t.Run("relative uri", func(t *testing.T) {
+		c := jsonschema.NewCompiler()
+		if err := c.RegisterDialect("dialect.json#", jsonschema.Draft4, nil); err != nil {
+			t.Fatal(err)
+		}
+		schema := `{"$schema": "` + toFileURL("dialect.json") + `", "minimum": 5, "exclusiveMinimum": true}`
+		if err := c.AddResource("schema.json", strings.NewReader(schema)); err != nil {
+			t.Fatal(err)
+		}
+		sch, err := c.Compile("schema.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := sch.Validate(decodeString(t, `5`)); err == nil {
+			t.Error("validation must fail")
+		}
+	})
+
+	t.Run("supported draft", func(t *testing.T) {
+		c := jsonschema.NewCompiler()
+		for _, uri := range []string{"https://json-schema.org/draft/2020-12/schema", "http://json-schema.org/draft-07/schema#"} {
+			if err := c.RegisterDialect(uri, jsonschema.Draft4, nil); err == nil {
+				t.Errorf("%s: error expected", uri)
+			}
+		}
+	})
+}
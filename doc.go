@@ -2,11 +2,30 @@
 Package jsonschema provides json-schema compilation and validation.
 
 Features:
- - implements draft 2020-12, 2019-09, draft-7, draft-6, draft-4
+ - implements draft 2020-12, 2019-09, draft-7, draft-6, draft-4, draft-3
+ - opt-in DraftNext, tracking the in-progress specification; supports propertyDependencies and contains on objects only
+ - OpenAPI30 dialect for OpenAPI 3.0 Schema Objects, supporting nullable and discriminator
+ - loads schemas from OpenAPI 3.0/3.1 documents, see Compiler.AddOpenAPI
+ - selects oneOf/anyOf alternative using OpenAPI discriminator, opt-in for json-schema drafts via Compiler.Discriminator
+ - custom error messages using errorMessage keyword, opt-in via Compiler.ErrorMessage
+ - localized validation error messages, see Compiler.Translator
+ - typed error details per keyword, see ValidationError.Kind
+ - deterministic order of validation errors
+ - applies and validates JSON Patch and JSON Merge Patch, see Schema.ValidatePatch
+ - navigates subschemas and validates sub-instances by json-pointer, see Schema.At and Schema.ValidateAt
+ - JSON Pointer and Relative JSON Pointer library, see package jsonpointer
+ - $data references for dynamic keyword values, opt-in via Compiler.Data
+ - validates default, examples, const and enum values at compile time, opt-in via Compiler.CheckValues
+ - strict mode for unknown keywords and formats, opt-in via Compiler.Strict
+ - schema linter with configurable rules, see Compiler.Lint and jv lint
+ - static analysis for unsatisfiable schemas, overlapping oneOf branches and redundant keywords, see Schema.Analyze
  - fully compliant with JSON-Schema-Test-Suite, (excluding some optional)
    - list of optional tests that are excluded can be found in schema_test.go(variable skipTests)
  - validates schemas against meta-schema
  - full support of remote references
+ - bundles schema and its remote references into single compound document
+ - dereferences schema into standalone document with references inlined
+ - migrates schema documents between drafts
  - support of recursive references between schemas
  - detects infinite loop in schemas
  - thread safe validation
@@ -15,7 +34,10 @@ Features:
  - supports enabling format and content Assertions in draft2019-09 or above
    - change Compiler.AssertFormat, Compiler.AssertContent to true
  - compiled schema can be introspected. easier to develop tools like generating go structs given schema
+ - compiled schema can be serialized back to json-schema document
  - supports user-defined keywords via extensions
+ - supports custom meta-schemas (dialects), honoring `$vocabulary` in draft2019-09 or above
+ - supports registering dialects programmatically, see `Compiler.RegisterDialect`
  - implements following formats (supports user-defined)
    - date-time, date, time, duration (supports leap-second)
    - uuid, hostname, email
@@ -28,6 +50,7 @@ Features:
  - implements following contentMediaType (supports user-defined)
    - application/json
  - can load from files/http/https/string/[]byte/io.Reader (suports user-defined)
+ - schemas can be constructed programmatically using Builder
 
 The schema is compiled against the version specified in "$schema" property.
 If "$schema" property is missing, it uses latest draft which currently implemented
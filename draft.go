@@ -7,11 +7,18 @@ import (
 
 // A Draft represents json-schema draft
 type Draft struct {
-	version    int
-	meta       *Schema
-	id         string // property name used to represent schema id.
-	boolSchema bool   // is boolean valid schema
-	subschemas map[string]position
+	version      int
+	url          string // canonical url of meta-schema.
+	meta         *Schema
+	id           string // property name used to represent schema id.
+	boolSchema   bool   // is boolean valid schema
+	subschemas   map[string]position
+	keywords     []string            // known keywords. used by Compiler.Strict.
+	vocabPrefix  string              // prefix of standard vocabulary uris.
+	vocabularies map[string][]string // standard vocabularies with their keywords.
+	vocab        []string            // enabled vocabularies. nil means default vocabularies.
+	exts         []string            // enabled extensions. nil means all extensions.
+	openapi      bool                // is OpenAPI schema object
 }
 
 func (d *Draft) loadMeta(base string, schemas map[string]string) {
@@ -34,6 +41,10 @@ func (d *Draft) getID(sch interface{}) string {
 		// $ref prevents a sibling id from changing the base uri
 		return ""
 	}
+	if d.id == "" {
+		// schema ids are not supported
+		return ""
+	}
 	v, ok := m.Get(d.id)
 	if !ok {
 		return ""
@@ -122,6 +133,10 @@ func (d *Draft) listSubschemas(r *resource, base string, rr map[string]*resource
 		if pos&item != 0 {
 			if v, ok := v.([]interface{}); ok {
 				for i, item := range v {
+					if !d.isSchema(item) {
+						// ex: type names in draft3 union types
+						continue
+					}
 					if err := add(kw+"/"+strconv.Itoa(i), item); err != nil {
 						return err
 					}
@@ -131,31 +146,76 @@ func (d *Draft) listSubschemas(r *resource, base string, rr map[string]*resource
 		if pos&prop != 0 {
 			if v, ok := v.(*OrderedMap); ok {
 				for pname, pval := range v.RawValues() {
+					if kw == "dependencies" && !d.isSchema(pval) {
+						// property dependencies
+						continue
+					}
 					if err := add(kw+"/"+escape(pname), pval); err != nil {
 						return err
 					}
 				}
 			}
 		}
+		if pos&propProp != 0 {
+			if v, ok := v.(*OrderedMap); ok {
+				for pname, pval := range v.RawValues() {
+					if pval, ok := pval.(*OrderedMap); ok {
+						for value, sch := range pval.RawValues() {
+							if err := add(kw+"/"+escape(pname)+"/"+escape(value), sch); err != nil {
+								return err
+							}
+						}
+					}
+				}
+			}
+		}
 	}
 	return nil
 }
 
+// isSchema tells whether v can be a schema in this draft.
+func (d *Draft) isSchema(v interface{}) bool {
+	switch v.(type) {
+	case *OrderedMap:
+		return true
+	case bool:
+		return d.boolSchema
+	}
+	return false
+}
+
 type position uint
 
 const (
 	self position = 1 << iota
 	prop
 	item
+	propProp // map of properties to map of values to schema
 )
 
 // supported drafts
 var (
-	Draft4    = &Draft{version: 4, id: "id", boolSchema: false}
-	Draft6    = &Draft{version: 6, id: "$id", boolSchema: true}
-	Draft7    = &Draft{version: 7, id: "$id", boolSchema: true}
-	Draft2019 = &Draft{version: 2019, id: "$id", boolSchema: true}
-	Draft2020 = &Draft{version: 2020, id: "$id", boolSchema: true}
+	Draft3    = &Draft{version: 3, url: "http://json-schema.org/draft-03/schema", id: "id", boolSchema: false}
+	Draft4    = &Draft{version: 4, url: "http://json-schema.org/draft-04/schema", id: "id", boolSchema: false}
+	Draft6    = &Draft{version: 6, url: "http://json-schema.org/draft-06/schema", id: "$id", boolSchema: true}
+	Draft7    = &Draft{version: 7, url: "http://json-schema.org/draft-07/schema", id: "$id", boolSchema: true}
+	Draft2019 = &Draft{version: 2019, url: "https://json-schema.org/draft/2019-09/schema", id: "$id", boolSchema: true, vocabPrefix: "https://json-schema.org/draft/2019-09/vocab/"}
+	Draft2020 = &Draft{version: 2020, url: "https://json-schema.org/draft/2020-12/schema", id: "$id", boolSchema: true, vocabPrefix: "https://json-schema.org/draft/2020-12/vocab/"}
+
+	// DraftNext tracks the in-progress specification, which is subject to change.
+	// It supports "propertyDependencies" and "contains" applied to objects.
+	// The changed "$dynamicRef" rules and output format are not supported yet.
+	// It is not used as latest draft.
+	DraftNext = &Draft{version: 9999, url: "https://json-schema.org/draft/future/schema", id: "$id", boolSchema: true, vocabPrefix: "https://json-schema.org/draft/future/vocab/"}
+
+	// OpenAPI30 is the dialect of Schema Object in OpenAPI 3.0, which is an extended
+	// subset of draft-04 with "nullable" and "discriminator" keywords. It does not
+	// support schema ids and "$schema", so it must be selected using Compiler.Draft.
+	OpenAPI30 = &Draft{version: 4, id: "", boolSchema: false, openapi: true}
+
+	// OpenAPI31 is the base dialect of Schema Object in OpenAPI 3.1, which is
+	// draft2020-12 with "discriminator" keyword.
+	OpenAPI31 = &Draft{version: 2020, url: "https://spec.openapis.org/oas/3.1/dialect/base", id: "$id", boolSchema: true, vocabPrefix: "https://json-schema.org/draft/2020-12/vocab/", openapi: true}
 
 	latest = Draft2020
 )
@@ -170,6 +230,10 @@ func findDraft(url string) *Draft {
 	switch url {
 	case "https://json-schema.org/schema":
 		return latest
+	case "https://json-schema.org/draft/future/schema":
+		return DraftNext
+	case "https://spec.openapis.org/oas/3.1/dialect/base":
+		return OpenAPI31
 	case "https://json-schema.org/draft/2020-12/schema":
 		return Draft2020
 	case "https://json-schema.org/draft/2019-09/schema":
@@ -180,6 +244,8 @@ func findDraft(url string) *Draft {
 		return Draft6
 	case "https://json-schema.org/draft-04/schema":
 		return Draft4
+	case "https://json-schema.org/draft-03/schema":
+		return Draft3
 	}
 	return nil
 }
@@ -188,10 +254,9 @@ func init() {
 	subschemas := map[string]position{
 		// type agnostic
 		"definitions": prop,
-		"not":         self,
-		"allOf":       item,
-		"anyOf":       item,
-		"oneOf":       item,
+		"type":        item,
+		"disallow":    item,
+		"extends":     self | item,
 		// object
 		"properties":           prop,
 		"additionalProperties": self,
@@ -201,7 +266,25 @@ func init() {
 		"additionalItems": self,
 		"dependencies":    prop,
 	}
+	Draft3.subschemas = clone(subschemas)
+
+	delete(subschemas, "type")
+	delete(subschemas, "disallow")
+	delete(subschemas, "extends")
+	subschemas["not"] = self
+	subschemas["allOf"] = item
+	subschemas["anyOf"] = item
+	subschemas["oneOf"] = item
 	Draft4.subschemas = clone(subschemas)
+	OpenAPI30.subschemas = map[string]position{
+		"not":                  self,
+		"allOf":                item,
+		"anyOf":                item,
+		"oneOf":                item,
+		"properties":           prop,
+		"additionalProperties": self,
+		"items":                self,
+	}
 
 	subschemas["propertyNames"] = self
 	subschemas["contains"] = self
@@ -220,7 +303,210 @@ func init() {
 
 	subschemas["prefixItems"] = item
 	Draft2020.subschemas = clone(subschemas)
+	OpenAPI31.subschemas = clone(subschemas)
+
+	subschemas["propertyDependencies"] = propProp
+	DraftNext.subschemas = clone(subschemas)
+
+	keywords := []string{
+		"$schema", "id", "$ref", "definitions", "title", "description", "default", "format",
+		"type", "disallow", "extends", "enum",
+		"properties", "patternProperties", "additionalProperties", "required", "dependencies",
+		"items", "additionalItems", "minItems", "maxItems", "uniqueItems",
+		"minLength", "maxLength", "pattern",
+		"minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum", "divisibleBy",
+	}
+	Draft3.keywords = keywords
+
+	keywords = without(keywords, "disallow", "extends", "divisibleBy")
+	keywords = extend(keywords, "not", "allOf", "anyOf", "oneOf", "minProperties", "maxProperties", "multipleOf")
+	Draft4.keywords = keywords
+	OpenAPI30.keywords = extend(without(keywords, "$schema", "id", "definitions", "patternProperties", "dependencies", "additionalItems"),
+		"nullable", "discriminator", "readOnly", "writeOnly", "example", "externalDocs", "deprecated", "xml")
+
+	keywords = extend(without(keywords, "id"), "$id", "const", "contains", "propertyNames", "examples")
+	Draft6.keywords = keywords
 
+	keywords = extend(keywords, "if", "then", "else", "contentEncoding", "contentMediaType", "$comment", "readOnly", "writeOnly")
+	Draft7.keywords = keywords
+
+	keywords = extend(keywords, "$defs", "$anchor", "$recursiveRef", "$recursiveAnchor", "$vocabulary",
+		"dependentRequired", "dependentSchemas", "unevaluatedProperties", "unevaluatedItems",
+		"minContains", "maxContains", "deprecated", "contentSchema")
+	Draft2019.keywords = keywords
+
+	keywords = extend(without(keywords, "$recursiveRef", "$recursiveAnchor", "additionalItems"), "$dynamicRef", "$dynamicAnchor", "prefixItems")
+	Draft2020.keywords = keywords
+	OpenAPI31.keywords = extend(keywords, "discriminator", "example", "externalDocs", "xml")
+
+	DraftNext.keywords = extend(keywords, "propertyDependencies")
+
+	vocabularies := map[string][]string{
+		"core":       nil,
+		"applicator": {"additionalItems", "unevaluatedItems", "items", "contains", "additionalProperties", "unevaluatedProperties", "properties", "patternProperties", "dependentSchemas", "propertyNames", "if", "then", "else", "allOf", "anyOf", "oneOf", "not"},
+		"validation": {"type", "const", "enum", "multipleOf", "maximum", "exclusiveMaximum", "minimum", "exclusiveMinimum", "maxLength", "minLength", "pattern", "maxItems", "minItems", "uniqueItems", "maxContains", "minContains", "maxProperties", "minProperties", "required", "dependentRequired"},
+		"meta-data":  {"title", "description", "default", "deprecated", "readOnly", "writeOnly", "examples"},
+		"format":     {"format"},
+		"content":    {"contentEncoding", "contentMediaType", "contentSchema"},
+	}
+	Draft2019.vocabularies = vocabularies
+
+	vocabularies = cloneVocabularies(vocabularies)
+	vocabularies["applicator"] = []string{"prefixItems", "items", "contains", "additionalProperties", "properties", "patternProperties", "dependentSchemas", "propertyNames", "if", "then", "else", "allOf", "anyOf", "oneOf", "not"}
+	vocabularies["unevaluated"] = []string{"unevaluatedItems", "unevaluatedProperties"}
+	delete(vocabularies, "format")
+	vocabularies["format-annotation"] = []string{"format"}
+	vocabularies["format-assertion"] = []string{"format"}
+	Draft2020.vocabularies = vocabularies
+	OpenAPI31.vocabularies = vocabularies
+
+	vocabularies = cloneVocabularies(vocabularies)
+	vocabularies["applicator"] = append(vocabularies["applicator"], "propertyDependencies")
+	DraftNext.vocabularies = vocabularies
+
+	Draft3.loadMeta("http://json-schema.org/draft-03", map[string]string{
+		"schema": `{
+			"$schema": "http://json-schema.org/draft-03/schema#",
+			"id": "http://json-schema.org/draft-03/schema#",
+			"type": "object",
+			"properties": {
+				"type": {
+					"type": ["string", "array"],
+					"items": {
+						"type": ["string", {"$ref": "#"}]
+					},
+					"uniqueItems": true,
+					"default": "any"
+				},
+				"properties": {
+					"type": "object",
+					"additionalProperties": {"$ref": "#", "type": "object"},
+					"default": {}
+				},
+				"patternProperties": {
+					"type": "object",
+					"additionalProperties": {"$ref": "#"},
+					"default": {}
+				},
+				"additionalProperties": {
+					"type": [{"$ref": "#"}, "boolean"],
+					"default": {}
+				},
+				"items": {
+					"type": [{"$ref": "#"}, "array"],
+					"items": {"$ref": "#"},
+					"default": {}
+				},
+				"additionalItems": {
+					"type": [{"$ref": "#"}, "boolean"],
+					"default": {}
+				},
+				"required": {
+					"type": "boolean",
+					"default": false
+				},
+				"dependencies": {
+					"type": "object",
+					"additionalProperties": {
+						"type": ["string", "array", {"$ref": "#"}],
+						"items": {
+							"type": "string"
+						}
+					},
+					"default": {}
+				},
+				"minimum": {
+					"type": "number"
+				},
+				"maximum": {
+					"type": "number"
+				},
+				"exclusiveMinimum": {
+					"type": "boolean",
+					"default": false
+				},
+				"exclusiveMaximum": {
+					"type": "boolean",
+					"default": false
+				},
+				"minItems": {
+					"type": "integer",
+					"minimum": 0,
+					"default": 0
+				},
+				"maxItems": {
+					"type": "integer",
+					"minimum": 0
+				},
+				"uniqueItems": {
+					"type": "boolean",
+					"default": false
+				},
+				"pattern": {
+					"type": "string",
+					"format": "regex"
+				},
+				"minLength": {
+					"type": "integer",
+					"minimum": 0,
+					"default": 0
+				},
+				"maxLength": {
+					"type": "integer"
+				},
+				"enum": {
+					"type": "array",
+					"minItems": 1,
+					"uniqueItems": true
+				},
+				"default": {
+					"type": "any"
+				},
+				"title": {
+					"type": "string"
+				},
+				"description": {
+					"type": "string"
+				},
+				"format": {
+					"type": "string"
+				},
+				"divisibleBy": {
+					"type": "number",
+					"minimum": 0,
+					"exclusiveMinimum": true,
+					"default": 1
+				},
+				"disallow": {
+					"type": ["string", "array"],
+					"items": {
+						"type": ["string", {"$ref": "#"}]
+					},
+					"uniqueItems": true
+				},
+				"extends": {
+					"type": [{"$ref": "#"}, "array"],
+					"items": {"$ref": "#"},
+					"default": {}
+				},
+				"id": {
+					"type": "string"
+				},
+				"$ref": {
+					"type": "string"
+				},
+				"$schema": {
+					"type": "string",
+					"format": "uri"
+				}
+			},
+			"dependencies": {
+				"exclusiveMinimum": "minimum",
+				"exclusiveMaximum": "maximum"
+			},
+			"default": {}
+		}`,
+	})
 	Draft4.loadMeta("http://json-schema.org/draft-04", map[string]string{
 		"schema": `{
 			"$schema": "http://json-schema.org/draft-04/schema#",
@@ -1026,7 +1312,7 @@ func init() {
 			}
 		}`,
 	})
-	Draft2020.loadMeta("https://json-schema.org/draft/2020-12", map[string]string{
+	draft2020Meta := map[string]string{
 		"schema": `{
 			"$schema": "https://json-schema.org/draft/2020-12/schema",
 			"$id": "https://json-schema.org/draft/2020-12/schema",
@@ -1365,7 +1651,158 @@ func init() {
 				"contentSchema": { "$dynamicRef": "#meta" }
 			}
 		}`,
+	}
+	Draft2020.loadMeta("https://json-schema.org/draft/2020-12", draft2020Meta)
+
+	// meta-schemas of DraftNext are derived from draft2020-12
+	nextMeta := make(map[string]string, len(draft2020Meta))
+	for u, schema := range draft2020Meta {
+		nextMeta[u] = strings.ReplaceAll(schema, "/draft/2020-12/", "/draft/future/")
+	}
+	nextMeta["meta/applicator"] = strings.Replace(nextMeta["meta/applicator"], `"propertyNames": { "$dynamicRef": "#meta" },`, `"propertyDependencies": {
+					"type": "object",
+					"additionalProperties": {
+						"type": "object",
+						"additionalProperties": { "$dynamicRef": "#meta" }
+					},
+					"default": {}
+				},
+				"propertyNames": { "$dynamicRef": "#meta" },`, 1)
+	DraftNext.loadMeta("https://json-schema.org/draft/future", nextMeta)
+
+	OpenAPI30.loadMeta("https://spec.openapis.org/oas/3.0", map[string]string{
+		"schema": `{
+			"$schema": "http://json-schema.org/draft-04/schema#",
+			"description": "OpenAPI 3.0 Schema Object",
+			"type": "object",
+			"properties": {
+				"$ref": { "type": "string", "format": "uri-reference" },
+				"title": { "type": "string" },
+				"multipleOf": { "type": "number", "minimum": 0, "exclusiveMinimum": true },
+				"maximum": { "type": "number" },
+				"exclusiveMaximum": { "type": "boolean", "default": false },
+				"minimum": { "type": "number" },
+				"exclusiveMinimum": { "type": "boolean", "default": false },
+				"maxLength": { "type": "integer", "minimum": 0 },
+				"minLength": { "type": "integer", "minimum": 0, "default": 0 },
+				"pattern": { "type": "string", "format": "regex" },
+				"maxItems": { "type": "integer", "minimum": 0 },
+				"minItems": { "type": "integer", "minimum": 0, "default": 0 },
+				"uniqueItems": { "type": "boolean", "default": false },
+				"maxProperties": { "type": "integer", "minimum": 0 },
+				"minProperties": { "type": "integer", "minimum": 0, "default": 0 },
+				"required": {
+					"type": "array",
+					"items": { "type": "string" },
+					"minItems": 1,
+					"uniqueItems": true
+				},
+				"enum": { "type": "array", "items": {}, "minItems": 1 },
+				"type": { "type": "string", "enum": [ "array", "boolean", "integer", "number", "object", "string" ] },
+				"not": { "$ref": "#" },
+				"allOf": { "type": "array", "items": { "$ref": "#" } },
+				"oneOf": { "type": "array", "items": { "$ref": "#" } },
+				"anyOf": { "type": "array", "items": { "$ref": "#" } },
+				"items": { "$ref": "#" },
+				"properties": {
+					"type": "object",
+					"additionalProperties": { "$ref": "#" }
+				},
+				"additionalProperties": {
+					"anyOf": [ { "$ref": "#" }, { "type": "boolean" } ],
+					"default": true
+				},
+				"description": { "type": "string" },
+				"format": { "type": "string" },
+				"default": {},
+				"nullable": { "type": "boolean", "default": false },
+				"discriminator": {
+					"type": "object",
+					"required": [ "propertyName" ],
+					"properties": {
+						"propertyName": { "type": "string" },
+						"mapping": {
+							"type": "object",
+							"additionalProperties": { "type": "string" }
+						}
+					}
+				},
+				"readOnly": { "type": "boolean", "default": false },
+				"writeOnly": { "type": "boolean", "default": false },
+				"example": {},
+				"externalDocs": { "type": "object" },
+				"deprecated": { "type": "boolean", "default": false },
+				"xml": { "type": "object" }
+			},
+			"patternProperties": {
+				"^x-": {}
+			},
+			"additionalProperties": false
+		}`,
 	})
+	OpenAPI31.loadMeta("https://spec.openapis.org/oas/3.1", map[string]string{
+		"schema": `{
+			"$schema": "https://json-schema.org/draft/2020-12/schema",
+			"$id": "https://spec.openapis.org/oas/3.1/dialect/base",
+			"$dynamicAnchor": "meta",
+
+			"title": "OpenAPI 3.1 Schema Object Dialect",
+
+			"allOf": [
+				{ "$ref": "https://json-schema.org/draft/2020-12/schema" },
+				{ "$ref": "https://spec.openapis.org/oas/3.1/meta/base" }
+			]
+		}`,
+		"meta/base": `{
+			"$schema": "https://json-schema.org/draft/2020-12/schema",
+			"$id": "https://spec.openapis.org/oas/3.1/meta/base",
+			"$dynamicAnchor": "meta",
+
+			"title": "OpenAPI 3.1 base vocabulary meta-schema",
+
+			"type": ["object", "boolean"],
+			"properties": {
+				"example": true,
+				"discriminator": {
+					"type": "object",
+					"required": [ "propertyName" ],
+					"properties": {
+						"propertyName": { "type": "string" },
+						"mapping": {
+							"type": "object",
+							"additionalProperties": { "type": "string" }
+						}
+					}
+				},
+				"externalDocs": { "type": "object" },
+				"xml": { "type": "object" }
+			}
+		}`,
+	})
+}
+
+// extend returns copy of list, with given items appended.
+func extend(list []string, items ...string) []string {
+	return append(append([]string(nil), list...), items...)
+}
+
+// without returns copy of list, without given items.
+func without(list []string, items ...string) []string {
+	var result []string
+	for _, item := range list {
+		if !contains(items, item) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func cloneVocabularies(m map[string][]string) map[string][]string {
+	mm := make(map[string][]string)
+	for k, v := range m {
+		mm[k] = append([]string(nil), v...)
+	}
+	return mm
 }
 
 func clone(m map[string]position) map[string]position {
@@ -0,0 +1,163 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ErrorMessage captures "errorMessage" keyword, which overrides the messages of
+// validation errors of a schema. It is enabled using Compiler.ErrorMessage.
+//
+// errorMessage is either a string, which replaces all errors of the schema with
+// single error, or an object mapping keywords to messages:
+//
+//	{
+//	    "type": "must be an object",
+//	    "required": {"name": "name is mandatory"},
+//	    "properties": {"code": "code must be two letters followed by digits"},
+//	    "_": "used for errors of other keywords"
+//	}
+//
+// Messages can refer ${value}, the instance value, ${schema}, the value of the
+// failed keyword, and ${property}, the property name in "required" and
+// "properties" messages.
+type ErrorMessage struct {
+	Message    string            // replaces all errors, if not empty.
+	Keywords   map[string]string // keyword to message. key "_" is used for other keywords.
+	Required   map[string]string // property name to message, for missing required property.
+	Properties map[string]string // property name to message, for errors of property value.
+
+	params map[string]interface{} // keyword values, referred by ${schema}
+}
+
+func compileErrorMessage(res *resource, m *OrderedMap, v interface{}) (*ErrorMessage, error) {
+	invalid := fmt.Errorf("jsonschema: invalid errorMessage in %s", res)
+	if msg, ok := v.(string); ok {
+		return &ErrorMessage{Message: msg}, nil
+	}
+	obj, ok := v.(*OrderedMap)
+	if !ok {
+		return nil, invalid
+	}
+	toMessages := func(v interface{}) (map[string]string, bool) {
+		obj, ok := v.(*OrderedMap)
+		if !ok {
+			return nil, false
+		}
+		msgs := make(map[string]string)
+		for _, k := range obj.Keys() {
+			msg, _ := obj.Get(k)
+			if msgs[k], ok = msg.(string); !ok {
+				return nil, false
+			}
+		}
+		return msgs, true
+	}
+
+	em := &ErrorMessage{Keywords: make(map[string]string), params: make(map[string]interface{})}
+	for _, kw := range obj.Keys() {
+		msg, _ := obj.Get(kw)
+		_, isObject := msg.(*OrderedMap)
+		switch {
+		case kw == "properties":
+			if em.Properties, ok = toMessages(msg); !ok {
+				return nil, invalid
+			}
+		case kw == "required" && isObject:
+			if em.Required, ok = toMessages(msg); !ok {
+				return nil, invalid
+			}
+		default:
+			if em.Keywords[kw], ok = msg.(string); !ok {
+				return nil, invalid
+			}
+			if pvalue, ok := m.Get(kw); ok {
+				em.params[kw] = pvalue
+			}
+		}
+	}
+	return em, nil
+}
+
+// apply returns errs with messages overridden. base is keyword location of the
+// schema, v is the instance, and newError/validationError create error for
+// keyword of the schema.
+func (em *ErrorMessage) apply(errs []*ValidationError, base string, v interface{}, required []string, newError func(keyword, msg string) *ValidationError, validationError func(keyword string, kind ErrorKind) *ValidationError) []*ValidationError {
+	if em.Message != "" {
+		return []*ValidationError{newError("errorMessage", em.render(em.Message, v, nil, ""))}
+	}
+
+	override := func(e *ValidationError, msg string) *ValidationError {
+		ce := *e
+		ce.Message, ce.Causes = msg, nil
+		return &ce
+	}
+	obj, _ := v.(map[string]interface{})
+	var result []*ValidationError
+	for _, e := range errs {
+		rel := strings.TrimPrefix(e.KeywordLocation, base+"/")
+		kw, rest := rel, ""
+		if i := strings.IndexByte(rel, '/'); i != -1 {
+			kw, rest = rel[:i], rel[i+1:]
+		}
+		switch {
+		case kw == "properties" && em.Properties != nil:
+			pname := rest
+			if i := strings.IndexByte(rest, '/'); i != -1 {
+				pname = rest[:i]
+			}
+			pname = unescape(pname)
+			if msg, ok := em.Properties[pname]; ok {
+				e = override(e, em.render(msg, obj[pname], nil, pname))
+			}
+			result = append(result, e)
+			continue
+		case kw == "required" && em.Required != nil:
+			var missing []string
+			for _, pname := range required {
+				if _, ok := obj[pname]; ok {
+					continue
+				}
+				if msg, ok := em.Required[pname]; ok {
+					e := validationError("required", &RequiredError{Missing: []string{pname}})
+					result = append(result, override(e, em.render(msg, v, nil, pname)))
+				} else {
+					missing = append(missing, pname)
+				}
+			}
+			if len(missing) > 0 {
+				result = append(result, validationError("required", &RequiredError{Missing: missing}))
+			}
+			continue
+		}
+		if msg, ok := em.Keywords[kw]; ok {
+			e = override(e, em.render(msg, v, em.params[kw], ""))
+		} else if msg, ok := em.Keywords["_"]; ok {
+			e = override(e, em.render(msg, v, em.params[kw], ""))
+		}
+		result = append(result, e)
+	}
+	return result
+}
+
+func (em *ErrorMessage) render(msg string, value, schema interface{}, property string) string {
+	if !strings.Contains(msg, "${") {
+		return msg
+	}
+	str := func(v interface{}) string {
+		if s, ok := v.(string); ok {
+			return s
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(b)
+	}
+	return strings.NewReplacer(
+		"${value}", str(value),
+		"${schema}", str(schema),
+		"${property}", property,
+	).Replace(msg)
+}
@@ -3,6 +3,7 @@ package jsonschema
 import (
 	"bytes"
 	"fmt"
+	"reflect"
 	"strings"
 	"unicode/utf8"
 )
@@ -66,15 +67,62 @@ func (se *SchemaError) GoString() string {
 	return se.Error()
 }
 
+// ValueError is the error reported by Compiler.CheckValues, when the value of
+// "default", "examples", "const" or "enum" keyword is not valid against the
+// schema in which it appears.
+type ValueError struct {
+	// ValueLocation is the absolute location of the value.
+	// For example "http://example.com/schema.json#/properties/age/examples/1".
+	ValueLocation string
+
+	// Err tells why the value is not valid.
+	Err *ValidationError
+}
+
+func (e *ValueError) Unwrap() error {
+	return e.Err
+}
+
+func (e *ValueError) Error() string {
+	err := e.Err.BestMatch()
+	return fmt.Sprintf("jsonschema: value at %s does not validate with %s: %s", e.ValueLocation+err.InstanceLocation, err.AbsoluteKeywordLocation, err.Message)
+}
+
 // ValidationError is the error type returned by Validate.
 type ValidationError struct {
 	KeywordLocation         string             // validation path of validating keyword or schema
 	AbsoluteKeywordLocation string             // absolute location of validating keyword or schema
 	InstanceLocation        string             // location of the json value within the instance being validated
 	Message                 string             // describes error
+	Keyword                 string             // keyword that failed. empty if error just wraps its causes
+	Kind                    ErrorKind          // details of error. nil if error just wraps its causes, or raised by extension
 	Causes                  []*ValidationError // nested validation errors
 }
 
+// As finds the first error among ve and its causes, whose Kind can be
+// assigned to target, and if found, sets target to that Kind.
+// This enables errors.As to be used with ErrorKind types:
+//
+//	var minLength *jsonschema.MinLengthError
+//	if errors.As(err, &minLength) {
+//		fmt.Println(minLength.Want, minLength.Got)
+//	}
+func (ve *ValidationError) As(target interface{}) bool {
+	if ve.Kind != nil {
+		t := reflect.ValueOf(target).Elem()
+		if k := reflect.ValueOf(ve.Kind); k.Type().AssignableTo(t.Type()) {
+			t.Set(k)
+			return true
+		}
+	}
+	for _, cause := range ve.Causes {
+		if cause.As(target) {
+			return true
+		}
+	}
+	return false
+}
+
 func (ve *ValidationError) add(causes ...error) error {
 	for _, cause := range causes {
 		ve.Causes = append(ve.Causes, cause.(*ValidationError))
@@ -84,28 +132,113 @@ func (ve *ValidationError) add(causes ...error) error {
 
 func (ve *ValidationError) causes(err error) error {
 	if err := err.(*ValidationError); err.Message == "" {
-		ve.Causes = err.Causes
+		ve.Causes = append(ve.Causes, err.Causes...)
 	} else {
 		ve.add(err)
 	}
 	return ve
 }
 
-func (ve *ValidationError) leaf() *ValidationError {
-	if strings.HasSuffix(ve.KeywordLocation, "/anyOf") || strings.HasSuffix(ve.KeywordLocation, "/oneOf") {
+// BestMatch returns the most relevant leaf error among ve and its causes.
+// This is useful in reporting single error to the user.
+//
+// For failed anyOf/oneOf, the alternative that is most likely intended is
+// chosen using following heuristics, in order of preference:
+//   - alternatives whose "type", "const" or "enum" matched the instance,
+//     and whose "const" or "enum" matched the properties of instance
+//   - alternatives which failed deeper in the instance
+//   - alternatives with fewer errors
+//
+// If no single alternative is most relevant, the anyOf/oneOf error is returned.
+func (ve *ValidationError) BestMatch() *ValidationError {
+	if isAlternatives(ve) {
 		if len(ve.Causes) == 1 {
-			return ve.Causes[0].leaf()
+			return ve.Causes[0].BestMatch()
+		}
+		if best := bestAlternative(ve); best != nil {
+			return best.BestMatch()
 		}
 		return ve
 	}
 	if len(ve.Causes) > 0 {
-		return ve.Causes[0].leaf()
+		return ve.Causes[0].BestMatch()
 	}
 	return ve
 }
 
+func isAlternatives(ve *ValidationError) bool {
+	return strings.HasSuffix(ve.KeywordLocation, "/anyOf") || strings.HasSuffix(ve.KeywordLocation, "/oneOf")
+}
+
+// relevance of an alternative in failed anyOf/oneOf.
+type relevance struct {
+	mismatch bool // failed on type/const/enum of the instance, or const/enum of its property
+	depth    int  // depth of deepest failure in instance
+	errors   int  // number of leaf errors
+}
+
+func (r relevance) better(o relevance) bool {
+	if r.mismatch != o.mismatch {
+		return !r.mismatch
+	}
+	if r.depth != o.depth {
+		return r.depth > o.depth
+	}
+	return r.errors < o.errors
+}
+
+func relevanceOf(alt *ValidationError, instanceLocation string) relevance {
+	var r relevance
+	var walk func(ve *ValidationError, nested bool)
+	walk = func(ve *ValidationError, nested bool) {
+		if len(ve.Causes) == 0 {
+			r.errors++
+			if depth := strings.Count(ve.InstanceLocation, "/"); depth > r.depth {
+				r.depth = depth
+			}
+			if !nested {
+				kw := ve.KeywordLocation[strings.LastIndexByte(ve.KeywordLocation, '/')+1:]
+				switch {
+				case ve.InstanceLocation == instanceLocation:
+					r.mismatch = r.mismatch || kw == "type" || kw == "const" || kw == "enum"
+				case strings.HasPrefix(ve.InstanceLocation, instanceLocation+"/") && strings.LastIndexByte(ve.InstanceLocation, '/') == len(instanceLocation):
+					// const/enum of property, like a discriminator
+					r.mismatch = r.mismatch || kw == "const" || kw == "enum"
+				}
+			}
+			return
+		}
+		nested = nested || isAlternatives(ve)
+		for _, cause := range ve.Causes {
+			walk(cause, nested)
+		}
+	}
+	walk(alt, false)
+	return r
+}
+
+// bestAlternative returns the most relevant cause of failed anyOf/oneOf ve.
+// returns nil, if there is no single most relevant cause.
+func bestAlternative(ve *ValidationError) *ValidationError {
+	var best, second *relevance
+	var bestErr *ValidationError
+	for _, cause := range ve.Causes {
+		r := relevanceOf(cause, ve.InstanceLocation)
+		switch {
+		case best == nil || r.better(*best):
+			best, second, bestErr = &r, best, cause
+		case second == nil || r.better(*second):
+			second = &r
+		}
+	}
+	if second != nil && !best.better(*second) {
+		return nil
+	}
+	return bestErr
+}
+
 func (ve *ValidationError) Error() string {
-	err := ve.leaf()
+	err := ve.BestMatch()
 	u, _ := split(ve.AbsoluteKeywordLocation)
 	return fmt.Sprintf("jsonschema: %s does not validate with %s: %s", quote(err.InstanceLocation), u+"#"+err.KeywordLocation, err.Message)
 }
@@ -0,0 +1,406 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+func TestValidationError_BestMatch(t *testing.T) {
+	tests := []struct {
+		description string
+		schema      string
+		doc         string
+		keyword     string // keyword location of best match
+	}{
+		{
+			description: "type mismatch is less relevant",
+			schema:      `{"anyOf": [{"type": "string"}, {"type": "object", "required": ["a"]}]}`,
+			doc:         `{}`,
+			keyword:     "/anyOf/1/required",
+		},
+		{
+			description: "const mismatch is less relevant",
+			schema: `{"oneOf": [
+				{"properties": {"kind": {"const": "a"}}, "required": ["kind"]},
+				{"properties": {"kind": {"const": "b"}, "b": {"type": "integer"}}}
+			]}`,
+			doc:     `{"kind": "b", "b": "x"}`,
+			keyword: "/oneOf/1/properties/b/type",
+		},
+		{
+			description: "deeper failure is more relevant",
+			schema: `{"anyOf": [
+				{"required": ["b"]},
+				{"properties": {"a": {"properties": {"b": {"type": "integer"}}}}}
+			]}`,
+			doc:     `{"a": {"b": "x"}}`,
+			keyword: "/anyOf/1/properties/a/properties/b/type",
+		},
+		{
+			description: "fewer errors is more relevant",
+			schema:      `{"anyOf": [{"required": ["a"], "minProperties": 2}, {"required": ["b"]}]}`,
+			doc:         `{"c": 1}`,
+			keyword:     "/anyOf/1/required",
+		},
+		{
+			description: "ambiguous",
+			schema:      `{"anyOf": [{"required": ["a"]}, {"required": ["b"]}]}`,
+			doc:         `{"c": 1}`,
+			keyword:     "/anyOf",
+		},
+		{
+			description: "nested",
+			schema: `{"properties": {"x": {"anyOf": [
+				{"type": "integer"},
+				{"anyOf": [{"type": "boolean"}, {"type": "string", "minLength": 5}]}
+			]}}}`,
+			doc:     `{"x": "abc"}`,
+			keyword: "/properties/x/anyOf/1/anyOf/1/minLength",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			sch, err := jsonschema.CompileString("schema.json", test.schema)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = sch.Validate(decodeString(t, test.doc))
+			verr, ok := err.(*jsonschema.ValidationError)
+			if !ok {
+				t.Fatalf("got %v, want *ValidationError", err)
+			}
+			t.Logf("%#v", verr)
+			if got := verr.BestMatch().KeywordLocation; got != test.keyword {
+				t.Errorf("got %s, want %s", got, test.keyword)
+			}
+			if !strings.Contains(verr.Error(), "#"+test.keyword+":") {
+				t.Errorf("Error() does not use best match: %s", verr.Error())
+			}
+		})
+	}
+}
+
+func TestErrorMessage(t *testing.T) {
+	tests := []struct {
+		description string
+		schema      string
+		doc         string
+		messages    []string
+	}{
+		{
+			description: "string",
+			schema:      `{"type": "string", "pattern": "^[A-Z]{2}[0-9]+$", "errorMessage": "must be country code followed by number"}`,
+			doc:         `"in12"`,
+			messages:    []string{"must be country code followed by number"},
+		},
+		{
+			description: "string replaces all errors",
+			schema:      `{"minLength": 10, "pattern": "^[0-9]+$", "errorMessage": "invalid ${value}"}`,
+			doc:         `"abc"`,
+			messages:    []string{"invalid abc"},
+		},
+		{
+			description: "type mismatch",
+			schema:      `{"type": "object", "errorMessage": {"type": "must be object, but got ${value}"}}`,
+			doc:         `[1]`,
+			messages:    []string{"must be object, but got [1]"},
+		},
+		{
+			description: "keyword",
+			schema:      `{"pattern": "^[A-Z]{2}[0-9]+$", "minLength": 3, "errorMessage": {"pattern": "${value} must match ${schema}"}}`,
+			doc:         `"a"`,
+			messages:    []string{"length must be >= 3, but got 1", "a must match ^[A-Z]{2}[0-9]+$"},
+		},
+		{
+			description: "other keywords",
+			schema:      `{"maximum": 5, "multipleOf": 2, "errorMessage": {"maximum": "too big", "_": "invalid number ${value}"}}`,
+			doc:         `7`,
+			messages:    []string{"too big", "invalid number 7"},
+		},
+		{
+			description: "required",
+			schema:      `{"required": ["a", "b", "c"], "errorMessage": {"required": {"a": "${property} is mandatory", "b": "b is mandatory"}}}`,
+			doc:         `{}`,
+			messages:    []string{"a is mandatory", "b is mandatory", "missing properties: 'c'"},
+		},
+		{
+			description: "properties",
+			schema: `{
+				"properties": {
+					"code": {"type": "string", "pattern": "^[A-Z]{2}[0-9]+$"},
+					"age": {"type": "integer"}
+				},
+				"errorMessage": {"properties": {"code": "${property} ${value} is invalid"}}
+			}`,
+			doc:      `{"code": "in12", "age": "x"}`,
+			messages: []string{"code in12 is invalid", "expected integer, but got string"},
+		},
+		{
+			description: "nested",
+			schema:      `{"items": {"type": "integer", "errorMessage": "item must be integer"}}`,
+			doc:         `[1, "x"]`,
+			messages:    []string{"item must be integer"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			c := jsonschema.NewCompiler()
+			c.ErrorMessage = true
+			if err := c.AddResource("schema.json", strings.NewReader(test.schema)); err != nil {
+				t.Fatal(err)
+			}
+			sch, err := c.Compile("schema.json")
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = sch.Validate(decodeString(t, test.doc))
+			verr, ok := err.(*jsonschema.ValidationError)
+			if !ok {
+				t.Fatalf("got %v, want *ValidationError", err)
+			}
+			t.Logf("%#v", verr)
+			var messages []string
+			var collect func(ve *jsonschema.ValidationError)
+			collect = func(ve *jsonschema.ValidationError) {
+				if len(ve.Causes) == 0 {
+					messages = append(messages, ve.Message)
+				}
+				for _, c := range ve.Causes {
+					collect(c)
+				}
+			}
+			collect(verr)
+			if strings.Join(messages, "\n") != strings.Join(test.messages, "\n") {
+				t.Errorf("got %q, want %q", messages, test.messages)
+			}
+		})
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		sch, err := jsonschema.CompileString("schema.json", `{"type": "string", "errorMessage": "custom"}`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := sch.Validate(1); err == nil || strings.Contains(err.Error(), "custom") {
+			t.Errorf("got %v", err)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, schema := range []string{
+			`{"errorMessage": 1}`,
+			`{"errorMessage": {"type": 1}}`,
+			`{"errorMessage": {"properties": "x"}}`,
+			`{"errorMessage": {"required": {"a": 1}}}`,
+		} {
+			c := jsonschema.NewCompiler()
+			c.ErrorMessage = true
+			if err := c.AddResource("schema.json", strings.NewReader(schema)); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := c.Compile("schema.json"); err == nil {
+				t.Errorf("%s: error expected", schema)
+			}
+		}
+	})
+}
+
+func TestValidationError_Kind(t *testing.T) {
+	validate := func(t *testing.T, schema, doc string) error {
+		t.Helper()
+		sch, err := jsonschema.CompileString("schema.json", schema)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = sch.Validate(decodeString(t, doc))
+		if err == nil {
+			t.Fatal("validation must fail")
+		}
+		return err
+	}
+
+	t.Run("minLength", func(t *testing.T) {
+		err := validate(t, `{"properties": {"name": {"minLength": 5}}}`, `{"name": "abc"}`)
+		var kind *jsonschema.MinLengthError
+		if !errors.As(err, &kind) {
+			t.Fatalf("MinLengthError not found in %#v", err)
+		}
+		if kind.Want != 5 || kind.Got != 3 {
+			t.Errorf("got %+v", kind)
+		}
+	})
+
+	t.Run("required", func(t *testing.T) {
+		err := validate(t, `{"required": ["a", "b", "c"]}`, `{"b": 1}`)
+		var kind *jsonschema.RequiredError
+		if !errors.As(err, &kind) {
+			t.Fatalf("RequiredError not found in %#v", err)
+		}
+		if want := []string{"a", "c"}; !reflect.DeepEqual(kind.Missing, want) {
+			t.Errorf("got %q, want %q", kind.Missing, want)
+		}
+	})
+
+	t.Run("oneOf", func(t *testing.T) {
+		err := validate(t, `{"oneOf": [{"type": "integer"}, {"minimum": 0}, {"maximum": 10}]}`, `5`)
+		var kind *jsonschema.OneOfError
+		if !errors.As(err, &kind) {
+			t.Fatalf("OneOfError not found in %#v", err)
+		}
+		if want := []int{0, 1}; !reflect.DeepEqual(kind.Matched, want) {
+			t.Errorf("got %v, want %v", kind.Matched, want)
+		}
+	})
+
+	t.Run("keyword", func(t *testing.T) {
+		err := validate(t, `{"$ref": "#/$defs/a", "$defs": {"a": {"type": "string"}}}`, `1`)
+		ve := err.(*jsonschema.ValidationError)
+		var keywords []string
+		for ; ve != nil; ve = ve.Causes[0] {
+			keywords = append(keywords, ve.Keyword)
+			if _, ok := ve.Kind.(*jsonschema.TypeError); ok {
+				break
+			}
+		}
+		if want := []string{"", "$ref", "type"}; !reflect.DeepEqual(keywords, want) {
+			t.Errorf("got %q, want %q", keywords, want)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		err := validate(t, `{"type": "string"}`, `1`)
+		var kind *jsonschema.MinLengthError
+		if errors.As(err, &kind) {
+			t.Fatalf("got %+v", kind)
+		}
+	})
+
+	t.Run("output", func(t *testing.T) {
+		err := validate(t, `{"$id": "http://example.com/schema.json", "maxItems": 1}`, `[1, 2]`)
+		out := err.(*jsonschema.ValidationError).BasicOutput()
+		b, err := json.Marshal(out.Errors[len(out.Errors)-1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := `{"keywordLocation":"/maxItems","absoluteKeywordLocation":"http://example.com/schema.json#/maxItems","instanceLocation":"","error":"maximum 1 items required, but found 2 items","keyword":"maxItems","details":{"want":1,"got":2}}`
+		if got := string(b); got != want {
+			t.Errorf("got %s\nwant %s", got, want)
+		}
+	})
+
+	t.Run("output number", func(t *testing.T) {
+		for _, schema := range []string{`{"minimum": 0.5}`, `{"exclusiveMinimum": 0.5}`, `{"maximum": 0.05}`, `{"exclusiveMaximum": 0.1}`, `{"multipleOf": 0.25}`} {
+			err := validate(t, schema, `0.1`)
+			out := err.(*jsonschema.ValidationError).BasicOutput()
+			b, err := json.Marshal(out.Errors[len(out.Errors)-1].Details)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var details map[string]interface{}
+			if err := json.Unmarshal(b, &details); err != nil {
+				t.Fatal(err)
+			}
+			if _, ok := details["want"].(float64); !ok {
+				t.Errorf("%s: want must be number: %s", schema, b)
+			}
+		}
+		b, err := json.Marshal(&jsonschema.MinimumError{Want: big.NewRat(1, 2), Got: 0.1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := `{"want":0.5,"got":0.1}`; string(b) != want {
+			t.Errorf("got %s, want %s", b, want)
+		}
+	})
+}
+
+func TestValidationError_Order(t *testing.T) {
+	sch, err := jsonschema.CompileString("schema.json", `{
+		"patternProperties": {
+			"^z": {"type": "string"},
+			"^a": {"type": "string"}
+		},
+		"dependentRequired": {
+			"z1": ["y"],
+			"a1": ["b"]
+		},
+		"properties": {
+			"known": true
+		},
+		"additionalProperties": false
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	collect := func(err error) []string {
+		var locs []string
+		var f func(ve *jsonschema.ValidationError)
+		f = func(ve *jsonschema.ValidationError) {
+			if len(ve.Causes) == 0 {
+				locs = append(locs, ve.KeywordLocation+" "+ve.InstanceLocation+" "+ve.Message)
+			}
+			for _, c := range ve.Causes {
+				f(c)
+			}
+		}
+		f(err.(*jsonschema.ValidationError))
+		return locs
+	}
+	tests := []struct {
+		description string
+		doc         interface{}
+		want        []string
+	}{
+		{
+			description: "map",
+			doc:         decodeString(t, `{"a2": 1, "z1": 1, "a1": 1, "z2": 1, "c": 1, "b2": 1}`),
+			want: []string{
+				"/patternProperties/%5Ez/type /z1 expected string, but got number",
+				"/patternProperties/%5Ez/type /z2 expected string, but got number",
+				"/patternProperties/%5Ea/type /a1 expected string, but got number",
+				"/patternProperties/%5Ea/type /a2 expected string, but got number",
+				"/additionalProperties  additionalProperties 'b2', 'c' not allowed",
+				"/dependentRequired/z1/0  property 'y' is required, if 'z1' property exists",
+				"/dependentRequired/a1/0  property 'b' is required, if 'a1' property exists",
+			},
+		},
+		{
+			description: "ordered map",
+			doc: func() interface{} {
+				m := jsonschema.NewOrderedMap()
+				if err := m.UnmarshalJSON([]byte(`{"a2": 1, "z1": 1, "a1": 1, "z2": 1, "c": 1, "b2": 1}`)); err != nil {
+					t.Fatal(err)
+				}
+				return m
+			}(),
+			want: []string{
+				"/patternProperties/%5Ez/type /z1 expected string, but got number",
+				"/patternProperties/%5Ez/type /z2 expected string, but got number",
+				"/patternProperties/%5Ea/type /a2 expected string, but got number",
+				"/patternProperties/%5Ea/type /a1 expected string, but got number",
+				"/additionalProperties  additionalProperties 'c', 'b2' not allowed",
+				"/dependentRequired/z1/0  property 'y' is required, if 'z1' property exists",
+				"/dependentRequired/a1/0  property 'b' is required, if 'a1' property exists",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			for i := 0; i < 20; i++ {
+				err := sch.Validate(test.doc)
+				if err == nil {
+					t.Fatal("validation must fail")
+				}
+				if got := collect(err); !reflect.DeepEqual(got, test.want) {
+					t.Fatalf("got %q\nwant %q", got, test.want)
+				}
+			}
+		})
+	}
+}
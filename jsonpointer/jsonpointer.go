@@ -0,0 +1,352 @@
+// Package jsonpointer implements JSON Pointer as specified in RFC 6901,
+// and Relative JSON Pointer as specified in draft-handrews-relative-json-pointer-01.
+//
+// Documents are the values decoded by encoding/json into interface{},
+// i.e. map[string]interface{}, []interface{} and scalars, and may also
+// contain *jsonschema.OrderedMap.
+//
+// The locations reported in jsonschema.ValidationError can be resolved
+// back to values in instance:
+//
+//	ptr, err := jsonpointer.ParseLocation(ve.InstanceLocation)
+//	if err != nil {
+//		return err
+//	}
+//	v, err := ptr.Get(instance)
+package jsonpointer
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// Pointer is parsed JSON Pointer, which is list of unescaped reference tokens.
+// Empty Pointer refers to the whole document.
+type Pointer []string
+
+// Parse parses given JSON Pointer s.
+//
+// If s starts with "#", it is treated as URI fragment identifier
+// representation, in which tokens are percent-encoded.
+func Parse(s string) (Pointer, error) {
+	if strings.HasPrefix(s, "#") {
+		return ParseLocation(s[1:])
+	}
+	return parse(s, false)
+}
+
+// ParseLocation parses s, which is JSON Pointer with percent-encoded tokens.
+// Use this to parse ValidationError.InstanceLocation and ValidationError.KeywordLocation.
+func ParseLocation(s string) (Pointer, error) {
+	return parse(s, true)
+}
+
+// MustParse is like Parse but panics if s cannot be parsed.
+func MustParse(s string) Pointer {
+	ptr, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return ptr
+}
+
+func parse(s string, percentEncoded bool) (Pointer, error) {
+	if s == "" {
+		return Pointer{}, nil
+	}
+	if s[0] != '/' {
+		return nil, fmt.Errorf("jsonpointer: %q must start with '/'", s)
+	}
+	ptr := Pointer(strings.Split(s[1:], "/"))
+	for i, tok := range ptr {
+		if percentEncoded {
+			t, err := url.PathUnescape(tok)
+			if err != nil {
+				return nil, fmt.Errorf("jsonpointer: invalid percent-encoding in %q", s)
+			}
+			tok = t
+		}
+		for j := 0; j < len(tok); j++ {
+			if tok[j] == '~' && (j == len(tok)-1 || (tok[j+1] != '0' && tok[j+1] != '1')) {
+				return nil, fmt.Errorf("jsonpointer: invalid escape sequence in %q", s)
+			}
+		}
+		tok = strings.Replace(tok, "~1", "/", -1)
+		ptr[i] = strings.Replace(tok, "~0", "~", -1)
+	}
+	return ptr, nil
+}
+
+// Append returns new Pointer with given tokens appended to p.
+func (p Pointer) Append(tokens ...string) Pointer {
+	ptr := make(Pointer, 0, len(p)+len(tokens))
+	return append(append(ptr, p...), tokens...)
+}
+
+// AppendIndex returns new Pointer with given array index appended to p.
+func (p Pointer) AppendIndex(i int) Pointer {
+	return p.Append(strconv.Itoa(i))
+}
+
+// Parent returns the pointer to parent of value referred by p.
+// Returns nil, if p refers to whole document.
+func (p Pointer) Parent() Pointer {
+	if len(p) == 0 {
+		return nil
+	}
+	return p[: len(p)-1 : len(p)-1]
+}
+
+// String returns the JSON Pointer representation of p.
+func (p Pointer) String() string {
+	var sb strings.Builder
+	for _, tok := range p {
+		sb.WriteByte('/')
+		sb.WriteString(escape(tok))
+	}
+	return sb.String()
+}
+
+// Location returns p with percent-encoded tokens, in the form used
+// by ValidationError.InstanceLocation.
+func (p Pointer) Location() string {
+	var sb strings.Builder
+	for _, tok := range p {
+		sb.WriteByte('/')
+		sb.WriteString(url.PathEscape(escape(tok)))
+	}
+	return sb.String()
+}
+
+// Fragment returns the URI fragment identifier representation of p,
+// including leading "#".
+func (p Pointer) Fragment() string {
+	return "#" + p.Location()
+}
+
+func escape(tok string) string {
+	tok = strings.Replace(tok, "~", "~0", -1)
+	return strings.Replace(tok, "/", "~1", -1)
+}
+
+// Get returns the value referred by p in doc.
+func (p Pointer) Get(doc interface{}) (interface{}, error) {
+	for i, tok := range p {
+		switch v := doc.(type) {
+		case map[string]interface{}:
+			cv, ok := v[tok]
+			if !ok {
+				return nil, p.notFound(i)
+			}
+			doc = cv
+		case *jsonschema.OrderedMap:
+			cv, ok := v.Get(tok)
+			if !ok {
+				return nil, p.notFound(i)
+			}
+			doc = cv
+		case []interface{}:
+			index, err := p.index(i, len(v))
+			if err != nil {
+				return nil, err
+			}
+			if index == len(v) {
+				return nil, p.notFound(i)
+			}
+			doc = v[index]
+		default:
+			return nil, p.notFound(i)
+		}
+	}
+	return doc, nil
+}
+
+// Set sets the value referred by p in doc, and returns the updated document.
+// If parent is object, the property is added or replaced. If parent is array,
+// the item is replaced, or appended if the last token is "-" or the array length.
+// The parent must exist.
+//
+// doc is updated in place, but the returned value must be used,
+// because root and the appended arrays may be replaced.
+func (p Pointer) Set(doc, value interface{}) (interface{}, error) {
+	if len(p) == 0 {
+		return value, nil
+	}
+	return p.update(doc, func(parent interface{}) (interface{}, error) {
+		tok := p[len(p)-1]
+		switch v := parent.(type) {
+		case map[string]interface{}:
+			v[tok] = value
+		case *jsonschema.OrderedMap:
+			v.Set(tok, value)
+		case []interface{}:
+			index, err := p.index(len(p)-1, len(v))
+			if err != nil {
+				return nil, err
+			}
+			if index == len(v) {
+				return append(v, value), nil
+			}
+			v[index] = value
+		default:
+			return nil, fmt.Errorf("jsonpointer: parent of %q is not object or array", p.String())
+		}
+		return parent, nil
+	})
+}
+
+// Delete removes the value referred by p in doc, and returns the updated document.
+// Items after the removed array item are shifted.
+//
+// doc is updated in place, but the returned value must be used,
+// because arrays may be replaced.
+func (p Pointer) Delete(doc interface{}) (interface{}, error) {
+	if len(p) == 0 {
+		return nil, fmt.Errorf("jsonpointer: cannot delete whole document")
+	}
+	return p.update(doc, func(parent interface{}) (interface{}, error) {
+		tok := p[len(p)-1]
+		switch v := parent.(type) {
+		case map[string]interface{}:
+			if _, ok := v[tok]; !ok {
+				return nil, p.notFound(len(p) - 1)
+			}
+			delete(v, tok)
+		case *jsonschema.OrderedMap:
+			if _, ok := v.Get(tok); !ok {
+				return nil, p.notFound(len(p) - 1)
+			}
+			v.Delete(tok)
+		case []interface{}:
+			index, err := p.index(len(p)-1, len(v))
+			if err != nil {
+				return nil, err
+			}
+			if index == len(v) {
+				return nil, p.notFound(len(p) - 1)
+			}
+			return append(v[:index], v[index+1:]...), nil
+		default:
+			return nil, fmt.Errorf("jsonpointer: parent of %q is not object or array", p.String())
+		}
+		return parent, nil
+	})
+}
+
+// update replaces the parent of value referred by p with the value
+// returned by f, and returns the updated document.
+func (p Pointer) update(doc interface{}, f func(parent interface{}) (interface{}, error)) (interface{}, error) {
+	parent, err := p.Parent().Get(doc)
+	if err != nil {
+		return nil, err
+	}
+	newParent, err := f(parent)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := parent.([]interface{}); !ok {
+		return doc, nil
+	}
+	// array may be reallocated
+	return p.Parent().Set(doc, newParent)
+}
+
+// index returns the array index for token at i, in array of given length.
+// "-" returns length.
+func (p Pointer) index(i, length int) (int, error) {
+	tok := p[i]
+	if tok == "-" {
+		return length, nil
+	}
+	index, err := strconv.Atoi(tok)
+	if err != nil || index < 0 || strconv.Itoa(index) != tok {
+		return 0, fmt.Errorf("jsonpointer: invalid array index %q in %q", tok, p.String())
+	}
+	if index > length {
+		return 0, p.notFound(i)
+	}
+	return index, nil
+}
+
+func (p Pointer) notFound(i int) error {
+	return fmt.Errorf("jsonpointer: %q not found", p[:i+1].String())
+}
+
+// RelativePointer is parsed Relative JSON Pointer.
+type RelativePointer struct {
+	Up  int     // number of levels up from current location.
+	Key bool    // evaluates to the property name or array index, instead of value. i.e. ends with "#".
+	Ptr Pointer // pointer from the location Up levels up. empty if Key is true.
+}
+
+// ParseRelative parses given Relative JSON Pointer s.
+func ParseRelative(s string) (*RelativePointer, error) {
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	if i == 0 || (i > 1 && s[0] == '0') {
+		return nil, fmt.Errorf("jsonpointer: %q must start with non-negative integer", s)
+	}
+	up, err := strconv.Atoi(s[:i])
+	if err != nil {
+		return nil, fmt.Errorf("jsonpointer: invalid relative pointer %q: %v", s, err)
+	}
+	rp := &RelativePointer{Up: up}
+	if s[i:] == "#" {
+		rp.Key = true
+		return rp, nil
+	}
+	if rp.Ptr, err = Parse(s[i:]); err != nil {
+		return nil, err
+	}
+	return rp, nil
+}
+
+// String returns the Relative JSON Pointer representation of rp.
+func (rp *RelativePointer) String() string {
+	if rp.Key {
+		return strconv.Itoa(rp.Up) + "#"
+	}
+	return strconv.Itoa(rp.Up) + rp.Ptr.String()
+}
+
+// Resolve returns the pointer in document, referred by rp evaluated at
+// location from. Returns error if rp evaluates to key.
+func (rp *RelativePointer) Resolve(from Pointer) (Pointer, error) {
+	if rp.Key {
+		return nil, fmt.Errorf("jsonpointer: %q evaluates to key", rp.String())
+	}
+	if rp.Up > len(from) {
+		return nil, fmt.Errorf("jsonpointer: %q goes above root from %q", rp.String(), from.String())
+	}
+	return from[:len(from)-rp.Up].Append(rp.Ptr...), nil
+}
+
+// Eval evaluates rp in doc, starting from location from. Key is returned
+// as string for object property, and as int for array index.
+func (rp *RelativePointer) Eval(doc interface{}, from Pointer) (interface{}, error) {
+	if _, err := from.Get(doc); err != nil {
+		return nil, err
+	}
+	if !rp.Key {
+		ptr, err := rp.Resolve(from)
+		if err != nil {
+			return nil, err
+		}
+		return ptr.Get(doc)
+	}
+	if rp.Up >= len(from) {
+		return nil, fmt.Errorf("jsonpointer: %q has no key from %q", rp.String(), from.String())
+	}
+	loc := from[:len(from)-rp.Up]
+	parent, _ := loc.Parent().Get(doc)
+	if _, ok := parent.([]interface{}); ok {
+		return strconv.Atoi(loc[len(loc)-1])
+	}
+	return loc[len(loc)-1], nil
+}
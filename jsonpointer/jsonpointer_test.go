@@ -0,0 +1,254 @@
+package jsonpointer_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"github.com/santhosh-tekuri/jsonschema/v5/jsonpointer"
+)
+
+func decode(t *testing.T, s string) interface{} {
+	t.Helper()
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		s    string
+		want jsonpointer.Pointer // nil if invalid
+	}{
+		{"", jsonpointer.Pointer{}},
+		{"/", jsonpointer.Pointer{""}},
+		{"/a~1b/c~0d/0", jsonpointer.Pointer{"a/b", "c~d", "0"}},
+		{"/a%20b", jsonpointer.Pointer{"a%20b"}},
+		{"#/a%20b/c~1d", jsonpointer.Pointer{"a b", "c/d"}},
+		{"#", jsonpointer.Pointer{}},
+		{"a", nil},
+		{"/a~2", nil},
+		{"/a~", nil},
+		{"#/a%zz", nil},
+	}
+	for _, test := range tests {
+		got, err := jsonpointer.Parse(test.s)
+		if test.want == nil {
+			if err == nil {
+				t.Errorf("%q: want error, got %q", test.s, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: %v", test.s, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%q: got %q, want %q", test.s, got, test.want)
+		}
+	}
+}
+
+func TestPointer_String(t *testing.T) {
+	ptr := jsonpointer.Pointer{}.Append("a/b", "c~d").AppendIndex(1).Append("^x y")
+	if got, want := ptr.String(), "/a~1b/c~0d/1/^x y"; got != want {
+		t.Errorf("String: got %q, want %q", got, want)
+	}
+	if got, want := ptr.Location(), "/a~1b/c~0d/1/%5Ex%20y"; got != want {
+		t.Errorf("Location: got %q, want %q", got, want)
+	}
+	if got, want := ptr.Fragment(), "#/a~1b/c~0d/1/%5Ex%20y"; got != want {
+		t.Errorf("Fragment: got %q, want %q", got, want)
+	}
+	if got, want := ptr.Parent().String(), "/a~1b/c~0d/1"; got != want {
+		t.Errorf("Parent: got %q, want %q", got, want)
+	}
+	if ptr := jsonpointer.MustParse(ptr.Fragment()); !reflect.DeepEqual(ptr, jsonpointer.Pointer{"a/b", "c~d", "1", "^x y"}) {
+		t.Errorf("round trip: got %q", ptr)
+	}
+}
+
+func TestPointer_Get(t *testing.T) {
+	om := jsonschema.NewOrderedMap()
+	om.Set("b", []interface{}{"x", "y"})
+	doc := map[string]interface{}{"a": om, "": 1.0}
+	tests := []struct {
+		ptr  string
+		want interface{} // nil if not found
+	}{
+		{"", doc},
+		{"/", 1.0},
+		{"/a/b/1", "y"},
+		{"/a/b/2", nil},
+		{"/a/b/-", nil},
+		{"/a/b/01", nil},
+		{"/a/c", nil},
+		{"/a/b/0/x", nil},
+	}
+	for _, test := range tests {
+		got, err := jsonpointer.MustParse(test.ptr).Get(doc)
+		if test.want == nil {
+			if err == nil {
+				t.Errorf("%q: want error, got %v", test.ptr, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: %v", test.ptr, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%q: got %v, want %v", test.ptr, got, test.want)
+		}
+	}
+}
+
+func TestPointer_Set(t *testing.T) {
+	tests := []struct {
+		ptr   string
+		value string
+		want  string // empty if error
+	}{
+		{"", `1`, `1`},
+		{"/b", `2`, `{"a":{"x":[1,2]},"b":2}`},
+		{"/a/x/0", `3`, `{"a":{"x":[3,2]}}`},
+		{"/a/x/-", `3`, `{"a":{"x":[1,2,3]}}`},
+		{"/a/x/2", `3`, `{"a":{"x":[1,2,3]}}`},
+		{"/a/x/3", `3`, ``},
+		{"/a/y/0", `3`, ``},
+		{"/a/x/0/y", `3`, ``},
+	}
+	for _, test := range tests {
+		doc := decode(t, `{"a":{"x":[1,2]}}`)
+		got, err := jsonpointer.MustParse(test.ptr).Set(doc, decode(t, test.value))
+		if test.want == "" {
+			if err == nil {
+				t.Errorf("%q: want error, got %v", test.ptr, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: %v", test.ptr, err)
+			continue
+		}
+		if want := decode(t, test.want); !reflect.DeepEqual(got, want) {
+			t.Errorf("%q: got %v, want %v", test.ptr, got, want)
+		}
+	}
+}
+
+func TestPointer_Delete(t *testing.T) {
+	tests := []struct {
+		ptr  string
+		want string // empty if error
+	}{
+		{"/a/x", `{"a":{},"b":[1,2,3]}`},
+		{"/b/1", `{"a":{"x":1},"b":[1,3]}`},
+		{"/a/y", ``},
+		{"/b/3", ``},
+		{"/b/-", ``},
+		{"", ``},
+	}
+	for _, test := range tests {
+		doc := decode(t, `{"a":{"x":1},"b":[1,2,3]}`)
+		got, err := jsonpointer.MustParse(test.ptr).Delete(doc)
+		if test.want == "" {
+			if err == nil {
+				t.Errorf("%q: want error, got %v", test.ptr, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: %v", test.ptr, err)
+			continue
+		}
+		if want := decode(t, test.want); !reflect.DeepEqual(got, want) {
+			t.Errorf("%q: got %v, want %v", test.ptr, got, want)
+		}
+	}
+}
+
+func TestRelativePointer(t *testing.T) {
+	// examples from draft-handrews-relative-json-pointer-01 section 5.1
+	doc := decode(t, `{"foo": ["bar", "baz"], "highly": {"nested": {"objects": true}}}`)
+	tests := []struct {
+		from string
+		rel  string
+		want interface{} // nil if error
+	}{
+		{"/foo/1", "0", "baz"},
+		{"/foo/1", "1/0", "bar"},
+		{"/foo/1", "2/highly/nested/objects", true},
+		{"/foo/1", "0#", 1},
+		{"/foo/1", "1#", "foo"},
+		{"/highly/nested", "0/objects", true},
+		{"/highly/nested", "1/nested/objects", true},
+		{"/highly/nested", "2/foo/0", "bar"},
+		{"/highly/nested", "0#", "nested"},
+		{"/highly/nested", "1#", "highly"},
+		{"/highly/nested", "2#", nil},
+		{"/highly/nested", "3/foo", nil},
+		{"/highly/missing", "0", nil},
+	}
+	for _, test := range tests {
+		rp, err := jsonpointer.ParseRelative(test.rel)
+		if err != nil {
+			t.Errorf("%q: %v", test.rel, err)
+			continue
+		}
+		if rp.String() != test.rel {
+			t.Errorf("%q: String() returned %q", test.rel, rp.String())
+		}
+		got, err := rp.Eval(doc, jsonpointer.MustParse(test.from))
+		if test.want == nil {
+			if err == nil {
+				t.Errorf("%q from %q: want error, got %v", test.rel, test.from, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q from %q: %v", test.rel, test.from, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%q from %q: got %v, want %v", test.rel, test.from, got, test.want)
+		}
+	}
+
+	for _, s := range []string{"", "#", "01/a", "a", "1a", "-1/a"} {
+		if _, err := jsonpointer.ParseRelative(s); err == nil {
+			t.Errorf("%q: want error", s)
+		}
+	}
+}
+
+func TestParseLocation(t *testing.T) {
+	sch := jsonschema.MustCompileString("schema.json", `{"patternProperties": {".": {"items": {"type": "string"}}}}`)
+	doc := decode(t, `{"a b/c": ["x", 1]}`)
+	ve, ok := sch.Validate(doc).(*jsonschema.ValidationError)
+	if !ok {
+		t.Fatal("want *ValidationError")
+	}
+	loc := ve.BestMatch().InstanceLocation
+	if !strings.Contains(loc, "%20") {
+		t.Fatalf("instance location %q must be percent-encoded", loc)
+	}
+	ptr, err := jsonpointer.ParseLocation(loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := ptr.Get(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != 1.0 {
+		t.Errorf("got %v, want 1", v)
+	}
+	if ptr.Location() != loc {
+		t.Errorf("Location: got %q, want %q", ptr.Location(), loc)
+	}
+}
@@ -0,0 +1,553 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// ErrorKind gives the details of validation error, as typed parameters.
+// The concrete type tells which keyword failed:
+//
+//	switch kind := ve.Kind.(type) {
+//	case *jsonschema.MinLengthError:
+//		fmt.Println("length", kind.Got, "is less than", kind.Want)
+//	case *jsonschema.RequiredError:
+//		fmt.Println("missing", kind.Missing)
+//	}
+//
+// Use errors.As to find the first error of given kind, among a
+// ValidationError and its causes.
+type ErrorKind interface {
+	error
+
+	// Keyword returns the keyword that failed.
+	Keyword() string
+
+	// localize returns the message translated using t.
+	localize(t Translator) string
+}
+
+func quoteAll(a []string) string {
+	q := make([]string, len(a))
+	for i, s := range a {
+		q[i] = quote(s)
+	}
+	return strings.Join(q, ", ")
+}
+
+func ratFloat(r *big.Rat) float64 {
+	f, _ := r.Float64()
+	return f
+}
+
+// marshalRatKind returns json encoding of ErrorKind with given want and got,
+// where want is encoded as json number.
+func marshalRatKind(want *big.Rat, got interface{}) ([]byte, error) {
+	return json.Marshal(struct {
+		Want json.Number `json:"want"`
+		Got  interface{} `json:"got"`
+	}{ratToJSON(want), got})
+}
+
+// FalseSchemaError is the ErrorKind for boolean schema false.
+type FalseSchemaError struct{}
+
+func (*FalseSchemaError) Keyword() string { return "false" }
+func (k *FalseSchemaError) Error() string { return k.localize(nil) }
+func (k *FalseSchemaError) localize(t Translator) string {
+	return translate(t, "false")
+}
+
+// TypeError is the ErrorKind for "type" keyword.
+type TypeError struct {
+	Want []string `json:"want"` // allowed types.
+	Got  string   `json:"got"`  // type of the instance.
+}
+
+func (*TypeError) Keyword() string { return "type" }
+func (k *TypeError) Error() string { return k.localize(nil) }
+func (k *TypeError) localize(t Translator) string {
+	return translate(t, "type", strings.Join(k.Want, translate(t, "or")), k.Got)
+}
+
+// DisallowError is the ErrorKind for "disallow" keyword.
+type DisallowError struct {
+	Got string `json:"got"` // type of the instance.
+}
+
+func (*DisallowError) Keyword() string { return "disallow" }
+func (k *DisallowError) Error() string { return k.localize(nil) }
+func (k *DisallowError) localize(t Translator) string {
+	return translate(t, "disallow", k.Got)
+}
+
+// ConstError is the ErrorKind for "const" keyword.
+type ConstError struct {
+	Want interface{} `json:"want"`
+	Got  interface{} `json:"got"`
+}
+
+func (*ConstError) Keyword() string { return "const" }
+func (k *ConstError) Error() string { return k.localize(nil) }
+func (k *ConstError) localize(t Translator) string {
+	switch jsonType(k.Want) {
+	case "object", "array":
+		return translate(t, "const")
+	}
+	return translate(t, "constValue", k.Want)
+}
+
+// EnumError is the ErrorKind for "enum" keyword.
+type EnumError struct {
+	Want []interface{} `json:"want"`
+	Got  interface{}   `json:"got"`
+}
+
+func (*EnumError) Keyword() string { return "enum" }
+func (k *EnumError) Error() string { return k.localize(nil) }
+func (k *EnumError) localize(t Translator) string {
+	values := make([]string, len(k.Want))
+	for i, item := range k.Want {
+		switch jsonType(item) {
+		case "object", "array":
+			return translate(t, "enum")
+		}
+		values[i] = fmt.Sprintf("%#v", item)
+	}
+	if len(values) == 1 {
+		return translate(t, "enumValue", values[0])
+	}
+	return translate(t, "enumValues", strings.Join(values, ", "))
+}
+
+// FormatError is the ErrorKind for "format" keyword.
+type FormatError struct {
+	Want string      `json:"want"` // format name.
+	Got  interface{} `json:"got"`
+}
+
+func (*FormatError) Keyword() string { return "format" }
+func (k *FormatError) Error() string { return k.localize(nil) }
+func (k *FormatError) localize(t Translator) string {
+	got := k.Got
+	if s, ok := got.(string); ok {
+		got = quote(s)
+	}
+	return translate(t, "format", got, quote(k.Want))
+}
+
+// MinContainsError is the ErrorKind for "minContains" keyword.
+// It is also used for "contains" keyword, with Want 1.
+type MinContainsError struct {
+	Want int `json:"want"`
+	Got  int `json:"got"` // number of items matching contains.
+}
+
+func (*MinContainsError) Keyword() string { return "minContains" }
+func (k *MinContainsError) Error() string { return k.localize(nil) }
+func (k *MinContainsError) localize(t Translator) string {
+	return translate(t, "minContains", k.Want, k.Got)
+}
+
+// MaxContainsError is the ErrorKind for "maxContains" keyword.
+type MaxContainsError struct {
+	Want int `json:"want"`
+	Got  int `json:"got"` // number of items matching contains.
+}
+
+func (*MaxContainsError) Keyword() string { return "maxContains" }
+func (k *MaxContainsError) Error() string { return k.localize(nil) }
+func (k *MaxContainsError) localize(t Translator) string {
+	return translate(t, "maxContains", k.Want, k.Got)
+}
+
+// MinPropertiesError is the ErrorKind for "minProperties" keyword.
+type MinPropertiesError struct {
+	Want int `json:"want"`
+	Got  int `json:"got"`
+}
+
+func (*MinPropertiesError) Keyword() string { return "minProperties" }
+func (k *MinPropertiesError) Error() string { return k.localize(nil) }
+func (k *MinPropertiesError) localize(t Translator) string {
+	return translate(t, "minProperties", k.Want, k.Got)
+}
+
+// MaxPropertiesError is the ErrorKind for "maxProperties" keyword.
+type MaxPropertiesError struct {
+	Want int `json:"want"`
+	Got  int `json:"got"`
+}
+
+func (*MaxPropertiesError) Keyword() string { return "maxProperties" }
+func (k *MaxPropertiesError) Error() string { return k.localize(nil) }
+func (k *MaxPropertiesError) localize(t Translator) string {
+	return translate(t, "maxProperties", k.Want, k.Got)
+}
+
+// RequiredError is the ErrorKind for "required" keyword.
+type RequiredError struct {
+	Missing []string `json:"missing"` // missing properties.
+}
+
+func (*RequiredError) Keyword() string { return "required" }
+func (k *RequiredError) Error() string { return k.localize(nil) }
+func (k *RequiredError) localize(t Translator) string {
+	return translate(t, "required", quoteAll(k.Missing))
+}
+
+// DiscriminatorError is the ErrorKind for OpenAPI "discriminator" keyword.
+type DiscriminatorError struct {
+	Property string      `json:"property"`
+	Missing  bool        `json:"missing,omitempty"` // discriminator property is missing.
+	Got      interface{} `json:"got,omitempty"`     // value of discriminator property.
+	Want     []string    `json:"want,omitempty"`    // known values. set only when Got is unknown string.
+}
+
+func (*DiscriminatorError) Keyword() string { return "discriminator" }
+func (k *DiscriminatorError) Error() string { return k.localize(nil) }
+func (k *DiscriminatorError) localize(t Translator) string {
+	if k.Missing {
+		return translate(t, "discriminatorMissing", quote(k.Property))
+	}
+	if got, ok := k.Got.(string); ok {
+		return translate(t, "discriminatorValue", quote(got), quote(k.Property), quoteAll(k.Want))
+	}
+	return translate(t, "discriminatorType", quote(k.Property), jsonType(k.Got))
+}
+
+// RegexPropertyError is the ErrorKind for property name that is not valid
+// regex. used only in draft4 metaschema, to validate "patternProperties".
+type RegexPropertyError struct {
+	Property string `json:"property"`
+}
+
+func (*RegexPropertyError) Keyword() string { return "regexProperties" }
+func (k *RegexPropertyError) Error() string { return k.localize(nil) }
+func (k *RegexPropertyError) localize(t Translator) string {
+	return translate(t, "regexProperty", quote(k.Property))
+}
+
+// AdditionalPropertiesError is the ErrorKind for "additionalProperties" keyword.
+type AdditionalPropertiesError struct {
+	Properties []string `json:"properties"` // properties not allowed.
+}
+
+func (*AdditionalPropertiesError) Keyword() string { return "additionalProperties" }
+func (k *AdditionalPropertiesError) Error() string { return k.localize(nil) }
+func (k *AdditionalPropertiesError) localize(t Translator) string {
+	return translate(t, "additionalProperties", quoteAll(k.Properties))
+}
+
+// DependencyError is the ErrorKind for "dependencies" keyword, with
+// array of property names.
+type DependencyError struct {
+	Property string `json:"property"` // property that exists.
+	Missing  string `json:"missing"`  // property that is required, but missing.
+}
+
+func (*DependencyError) Keyword() string { return "dependencies" }
+func (k *DependencyError) Error() string { return k.localize(nil) }
+func (k *DependencyError) localize(t Translator) string {
+	return translate(t, "dependentRequired", quote(k.Missing), quote(k.Property))
+}
+
+// DependentRequiredError is the ErrorKind for "dependentRequired" keyword.
+type DependentRequiredError struct {
+	Property string `json:"property"` // property that exists.
+	Missing  string `json:"missing"`  // property that is required, but missing.
+}
+
+func (*DependentRequiredError) Keyword() string { return "dependentRequired" }
+func (k *DependentRequiredError) Error() string { return k.localize(nil) }
+func (k *DependentRequiredError) localize(t Translator) string {
+	return translate(t, "dependentRequired", quote(k.Missing), quote(k.Property))
+}
+
+// MinItemsError is the ErrorKind for "minItems" keyword.
+type MinItemsError struct {
+	Want int `json:"want"`
+	Got  int `json:"got"`
+}
+
+func (*MinItemsError) Keyword() string { return "minItems" }
+func (k *MinItemsError) Error() string { return k.localize(nil) }
+func (k *MinItemsError) localize(t Translator) string {
+	return translate(t, "minItems", k.Want, k.Got)
+}
+
+// MaxItemsError is the ErrorKind for "maxItems" keyword.
+type MaxItemsError struct {
+	Want int `json:"want"`
+	Got  int `json:"got"`
+}
+
+func (*MaxItemsError) Keyword() string { return "maxItems" }
+func (k *MaxItemsError) Error() string { return k.localize(nil) }
+func (k *MaxItemsError) localize(t Translator) string {
+	return translate(t, "maxItems", k.Want, k.Got)
+}
+
+// UniqueItemsError is the ErrorKind for "uniqueItems" keyword.
+type UniqueItemsError struct {
+	Duplicates [2]int `json:"duplicates"` // indexes of equal items.
+}
+
+func (*UniqueItemsError) Keyword() string { return "uniqueItems" }
+func (k *UniqueItemsError) Error() string { return k.localize(nil) }
+func (k *UniqueItemsError) localize(t Translator) string {
+	return translate(t, "uniqueItems", k.Duplicates[0], k.Duplicates[1])
+}
+
+// AdditionalItemsError is the ErrorKind for "additionalItems" keyword.
+type AdditionalItemsError struct {
+	Want int `json:"want"` // number of items allowed.
+	Got  int `json:"got"`
+}
+
+func (*AdditionalItemsError) Keyword() string { return "additionalItems" }
+func (k *AdditionalItemsError) Error() string { return k.localize(nil) }
+func (k *AdditionalItemsError) localize(t Translator) string {
+	return translate(t, "additionalItems", k.Want, k.Got)
+}
+
+// MinLengthError is the ErrorKind for "minLength" keyword.
+type MinLengthError struct {
+	Want int `json:"want"`
+	Got  int `json:"got"`
+}
+
+func (*MinLengthError) Keyword() string { return "minLength" }
+func (k *MinLengthError) Error() string { return k.localize(nil) }
+func (k *MinLengthError) localize(t Translator) string {
+	return translate(t, "minLength", k.Want, k.Got)
+}
+
+// MaxLengthError is the ErrorKind for "maxLength" keyword.
+type MaxLengthError struct {
+	Want int `json:"want"`
+	Got  int `json:"got"`
+}
+
+func (*MaxLengthError) Keyword() string { return "maxLength" }
+func (k *MaxLengthError) Error() string { return k.localize(nil) }
+func (k *MaxLengthError) localize(t Translator) string {
+	return translate(t, "maxLength", k.Want, k.Got)
+}
+
+// PatternError is the ErrorKind for "pattern" keyword.
+type PatternError struct {
+	Want string `json:"want"` // pattern.
+	Got  string `json:"got"`
+}
+
+func (*PatternError) Keyword() string { return "pattern" }
+func (k *PatternError) Error() string { return k.localize(nil) }
+func (k *PatternError) localize(t Translator) string {
+	return translate(t, "pattern", quote(k.Want))
+}
+
+// ContentEncodingError is the ErrorKind for "contentEncoding" keyword.
+type ContentEncodingError struct {
+	Want string `json:"want"` // encoding.
+	Got  string `json:"got"`
+}
+
+func (*ContentEncodingError) Keyword() string { return "contentEncoding" }
+func (k *ContentEncodingError) Error() string { return k.localize(nil) }
+func (k *ContentEncodingError) localize(t Translator) string {
+	return translate(t, "contentEncoding", quote(k.Got), k.Want)
+}
+
+// ContentMediaTypeError is the ErrorKind for "contentMediaType" keyword.
+type ContentMediaTypeError struct {
+	Want string `json:"want"` // media type.
+}
+
+func (*ContentMediaTypeError) Keyword() string { return "contentMediaType" }
+func (k *ContentMediaTypeError) Error() string { return k.localize(nil) }
+func (k *ContentMediaTypeError) localize(t Translator) string {
+	return translate(t, "contentMediaType", quote(k.Want))
+}
+
+// MinimumError is the ErrorKind for "minimum" keyword.
+type MinimumError struct {
+	Want *big.Rat    `json:"want"`
+	Got  interface{} `json:"got"`
+}
+
+func (*MinimumError) Keyword() string { return "minimum" }
+func (k *MinimumError) Error() string { return k.localize(nil) }
+func (k *MinimumError) localize(t Translator) string {
+	return translate(t, "minimum", ratFloat(k.Want), k.Got)
+}
+
+func (k *MinimumError) MarshalJSON() ([]byte, error) { return marshalRatKind(k.Want, k.Got) }
+
+// ExclusiveMinimumError is the ErrorKind for "exclusiveMinimum" keyword.
+type ExclusiveMinimumError struct {
+	Want *big.Rat    `json:"want"`
+	Got  interface{} `json:"got"`
+}
+
+func (*ExclusiveMinimumError) Keyword() string { return "exclusiveMinimum" }
+func (k *ExclusiveMinimumError) Error() string { return k.localize(nil) }
+func (k *ExclusiveMinimumError) localize(t Translator) string {
+	return translate(t, "exclusiveMinimum", ratFloat(k.Want), k.Got)
+}
+
+func (k *ExclusiveMinimumError) MarshalJSON() ([]byte, error) { return marshalRatKind(k.Want, k.Got) }
+
+// MaximumError is the ErrorKind for "maximum" keyword.
+type MaximumError struct {
+	Want *big.Rat    `json:"want"`
+	Got  interface{} `json:"got"`
+}
+
+func (*MaximumError) Keyword() string { return "maximum" }
+func (k *MaximumError) Error() string { return k.localize(nil) }
+func (k *MaximumError) localize(t Translator) string {
+	return translate(t, "maximum", ratFloat(k.Want), k.Got)
+}
+
+func (k *MaximumError) MarshalJSON() ([]byte, error) { return marshalRatKind(k.Want, k.Got) }
+
+// ExclusiveMaximumError is the ErrorKind for "exclusiveMaximum" keyword.
+type ExclusiveMaximumError struct {
+	Want *big.Rat    `json:"want"`
+	Got  interface{} `json:"got"`
+}
+
+func (*ExclusiveMaximumError) Keyword() string { return "exclusiveMaximum" }
+func (k *ExclusiveMaximumError) Error() string { return k.localize(nil) }
+func (k *ExclusiveMaximumError) localize(t Translator) string {
+	return translate(t, "exclusiveMaximum", ratFloat(k.Want), k.Got)
+}
+
+func (k *ExclusiveMaximumError) MarshalJSON() ([]byte, error) { return marshalRatKind(k.Want, k.Got) }
+
+// MultipleOfError is the ErrorKind for "multipleOf" keyword.
+type MultipleOfError struct {
+	Want *big.Rat    `json:"want"`
+	Got  interface{} `json:"got"`
+}
+
+func (*MultipleOfError) Keyword() string { return "multipleOf" }
+func (k *MultipleOfError) Error() string { return k.localize(nil) }
+func (k *MultipleOfError) localize(t Translator) string {
+	return translate(t, "multipleOf", k.Got, ratFloat(k.Want))
+}
+
+func (k *MultipleOfError) MarshalJSON() ([]byte, error) { return marshalRatKind(k.Want, k.Got) }
+
+// DivisibleByError is the ErrorKind for "divisibleBy" keyword.
+type DivisibleByError struct {
+	Want *big.Rat    `json:"want"`
+	Got  interface{} `json:"got"`
+}
+
+func (*DivisibleByError) Keyword() string { return "divisibleBy" }
+func (k *DivisibleByError) Error() string { return k.localize(nil) }
+func (k *DivisibleByError) localize(t Translator) string {
+	return translate(t, "divisibleBy", k.Got, ratFloat(k.Want))
+}
+
+func (k *DivisibleByError) MarshalJSON() ([]byte, error) { return marshalRatKind(k.Want, k.Got) }
+
+// RefError is the ErrorKind for "$ref", "$recursiveRef" and "$dynamicRef"
+// keywords. The causes of ValidationError tell why the referenced schema failed.
+type RefError struct {
+	URL     string `json:"url"` // location of referenced schema.
+	keyword string
+}
+
+func (k *RefError) Keyword() string { return k.keyword }
+func (k *RefError) Error() string   { return k.localize(nil) }
+func (k *RefError) localize(t Translator) string {
+	return translate(t, "schema", quote(k.URL))
+}
+
+// DataError is the ErrorKind for keywords with "$data" reference, when
+// the referenced value is not valid for the keyword.
+type DataError struct {
+	Pointer string      `json:"pointer"` // "$data" reference.
+	Want    string      `json:"want"`    // expected value, such as "number" or "regex".
+	Got     interface{} `json:"got"`     // referenced value.
+	keyword string
+}
+
+func (k *DataError) Keyword() string { return k.keyword }
+func (k *DataError) Error() string   { return k.localize(nil) }
+func (k *DataError) localize(t Translator) string {
+	return translate(t, "data", quote(k.Pointer), k.Want, k.Got)
+}
+
+// NotError is the ErrorKind for "not" keyword.
+type NotError struct{}
+
+func (*NotError) Keyword() string { return "not" }
+func (k *NotError) Error() string { return k.localize(nil) }
+func (k *NotError) localize(t Translator) string {
+	return translate(t, "not")
+}
+
+// ExtendsError is the ErrorKind for "extends" keyword.
+type ExtendsError struct{}
+
+func (*ExtendsError) Keyword() string { return "extends" }
+func (k *ExtendsError) Error() string { return k.localize(nil) }
+func (k *ExtendsError) localize(t Translator) string {
+	return translate(t, "extends")
+}
+
+// AllOfError is the ErrorKind for "allOf" keyword.
+type AllOfError struct{}
+
+func (*AllOfError) Keyword() string { return "allOf" }
+func (k *AllOfError) Error() string { return k.localize(nil) }
+func (k *AllOfError) localize(t Translator) string {
+	return translate(t, "allOf")
+}
+
+// AnyOfError is the ErrorKind for "anyOf" keyword.
+type AnyOfError struct{}
+
+func (*AnyOfError) Keyword() string { return "anyOf" }
+func (k *AnyOfError) Error() string { return k.localize(nil) }
+func (k *AnyOfError) localize(t Translator) string {
+	return translate(t, "anyOf")
+}
+
+// OneOfError is the ErrorKind for "oneOf" keyword.
+type OneOfError struct {
+	Matched []int `json:"matched,omitempty"` // indexes of subschemas matched. nil if none matched.
+}
+
+func (*OneOfError) Keyword() string { return "oneOf" }
+func (k *OneOfError) Error() string { return k.localize(nil) }
+func (k *OneOfError) localize(t Translator) string {
+	if len(k.Matched) < 2 {
+		return translate(t, "oneOf")
+	}
+	return translate(t, "oneOfMultiple", k.Matched[0], k.Matched[1])
+}
+
+// ThenError is the ErrorKind for "then" keyword.
+type ThenError struct{}
+
+func (*ThenError) Keyword() string { return "then" }
+func (k *ThenError) Error() string { return k.localize(nil) }
+func (k *ThenError) localize(t Translator) string {
+	return translate(t, "then")
+}
+
+// ElseError is the ErrorKind for "else" keyword.
+type ElseError struct{}
+
+func (*ElseError) Keyword() string { return "else" }
+func (k *ElseError) Error() string { return k.localize(nil) }
+func (k *ElseError) localize(t Translator) string {
+	return translate(t, "else")
+}
@@ -0,0 +1,424 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/url"
+	"regexp"
+	"regexp/syntax"
+	"sort"
+	"strings"
+)
+
+// LintSeverity tells how serious a Lint is.
+type LintSeverity string
+
+// Supported lint severities.
+const (
+	LintError   LintSeverity = "error"
+	LintWarning LintSeverity = "warning"
+	LintInfo    LintSeverity = "info"
+)
+
+// Lint is a best-practice diagnostic reported by Compiler.Lint.
+type Lint struct {
+	Rule     string       `json:"rule"`     // name of LintRule.
+	Severity LintSeverity `json:"severity"` // severity of LintRule.
+	Location string       `json:"location"` // absolute location of schema or keyword.
+	Message  string       `json:"message"`
+}
+
+func (l Lint) String() string {
+	return fmt.Sprintf("%s: %s: %s (%s)", l.Severity, l.Location, l.Message, l.Rule)
+}
+
+// LintRule is a check performed by Compiler.Lint.
+//
+// Rules are configured by choosing which of the DefaultLintRules to
+// pass to Compiler.Lint, and by changing their Severity.
+type LintRule struct {
+	Name        string
+	Description string
+	Severity    LintSeverity
+	check       func(l *linter, res *resource, m *OrderedMap)
+}
+
+// DefaultLintRules returns the built-in lint rules.
+func DefaultLintRules() []LintRule {
+	return []LintRule{
+		{"missing-schema", "root schema has no $schema", LintWarning, lintMissingSchema},
+		{"missing-id", "root schema has no $id", LintInfo, lintMissingID},
+		{"required-undefined", "required property is not defined in properties", LintWarning, lintRequiredUndefined},
+		{"unused-defs", "schema in $defs or definitions is not referenced", LintWarning, lintUnusedDefs},
+		{"unsatisfiable", "lower bound is greater than upper bound, such as minimum > maximum", LintError, lintUnsatisfiable},
+		{"additional-properties-allof", "additionalProperties false does not see properties declared in allOf, anyOf or oneOf", LintWarning, lintAdditionalPropertiesAllOf},
+		{"overlapping-pattern-properties", "property names can match more than one of patternProperties", LintInfo, lintOverlappingPatternProperties},
+	}
+}
+
+// Lint compiles the schema at url, and checks given rules against all
+// schemas loaded by the compiler. If rules is nil, DefaultLintRules are used.
+//
+// Returned error is same as Compile. Lints are sorted by location.
+func (c *Compiler) Lint(url string, rules []LintRule) ([]Lint, error) {
+	if _, err := c.Compile(url); err != nil {
+		return nil, err
+	}
+	if rules == nil {
+		rules = DefaultLintRules()
+	}
+
+	l := &linter{c: c}
+	urls := make([]string, 0, len(c.resources))
+	for url := range c.resources {
+		urls = append(urls, url)
+	}
+	sort.Strings(urls)
+	for _, url := range urls {
+		r := c.resources[url]
+		if r.draft == nil {
+			continue
+		}
+		l.r = r
+		for _, res := range l.schemas() {
+			m, ok := res.doc.(*OrderedMap)
+			if !ok {
+				continue
+			}
+			for _, rule := range rules {
+				l.rule = rule
+				rule.check(l, res, m)
+			}
+		}
+	}
+	sort.SliceStable(l.lints, func(i, j int) bool {
+		return l.lints[i].Location < l.lints[j].Location
+	})
+	return l.lints, nil
+}
+
+type linter struct {
+	c     *Compiler
+	r     *resource // root resource being linted
+	rule  LintRule
+	refs  []string // resolved references in all resources. computed lazily
+	lints []Lint
+}
+
+// schemas returns root resource and its subresources, sorted by location.
+func (l *linter) schemas() []*resource {
+	var rr []*resource
+	if !isOpenAPI(l.r.doc) {
+		rr = append(rr, l.r)
+	}
+	flocs := make([]string, 0, len(l.r.subresources))
+	for floc := range l.r.subresources {
+		flocs = append(flocs, floc)
+	}
+	sort.Strings(flocs)
+	for _, floc := range flocs {
+		rr = append(rr, l.r.subresources[floc])
+	}
+	return rr
+}
+
+// report adds lint for schema res. keywordPath is relative-json-pointer
+// to the keyword, and empty for schema itself.
+func (l *linter) report(res *resource, keywordPath string, format string, a ...interface{}) {
+	loc := l.r.url + res.floc
+	if keywordPath != "" {
+		loc += "/" + keywordPath
+	}
+	l.lints = append(l.lints, Lint{
+		Rule:     l.rule.Name,
+		Severity: l.rule.Severity,
+		Location: loc,
+		Message:  fmt.Sprintf(format, a...),
+	})
+}
+
+// references returns the resolved urls of "$ref", "$recursiveRef" and
+// "$dynamicRef" in all resources of the compiler.
+func (l *linter) references() []string {
+	if l.refs != nil {
+		return l.refs
+	}
+	l.refs = []string{}
+	for _, r := range l.c.resources {
+		if r.draft == nil {
+			continue
+		}
+		for _, res := range append([]*resource{r}, subresourceList(r)...) {
+			m, ok := res.doc.(*OrderedMap)
+			if !ok {
+				continue
+			}
+			for _, kw := range []string{"$ref", "$recursiveRef", "$dynamicRef"} {
+				if ref, ok := m.Get(kw); ok {
+					if ref, ok := ref.(string); ok {
+						if u, err := resolveURL(r.baseURL(res.floc), ref); err == nil {
+							l.refs = append(l.refs, unescapeURL(u))
+						}
+					}
+				}
+			}
+		}
+	}
+	return l.refs
+}
+
+func subresourceList(r *resource) []*resource {
+	rr := make([]*resource, 0, len(r.subresources))
+	for _, sr := range r.subresources {
+		rr = append(rr, sr)
+	}
+	return rr
+}
+
+func unescapeURL(u string) string {
+	if s, err := url.PathUnescape(u); err == nil {
+		return s
+	}
+	return u
+}
+
+func lintMissingSchema(l *linter, res *resource, m *OrderedMap) {
+	if res != l.r {
+		return
+	}
+	if _, ok := m.Get("$schema"); !ok {
+		l.report(res, "", "$schema is missing; draft used depends on compiler configuration")
+	}
+}
+
+func lintMissingID(l *linter, res *resource, m *OrderedMap) {
+	if res != l.r || l.r.draft.id == "" {
+		return
+	}
+	if _, ok := m.Get(l.r.draft.id); !ok {
+		l.report(res, "", "%s is missing; schema is identified by its retrieval url", l.r.draft.id)
+	}
+}
+
+func lintRequiredUndefined(l *linter, res *resource, m *OrderedMap) {
+	required, ok := m.RawValues()["required"].([]interface{})
+	if !ok {
+		return
+	}
+	props, ok := m.RawValues()["properties"].(*OrderedMap)
+	if !ok {
+		return
+	}
+	var patterns []*regexp.Regexp
+	if pprops, ok := m.RawValues()["patternProperties"].(*OrderedMap); ok {
+		for _, pattern := range pprops.Keys() {
+			if re, err := regexp.Compile(pattern); err == nil {
+				patterns = append(patterns, re)
+			}
+		}
+	}
+	var undefined []string
+loop:
+	for _, pname := range required {
+		pname, ok := pname.(string)
+		if !ok {
+			continue
+		}
+		if _, ok := props.Get(pname); ok {
+			continue
+		}
+		for _, re := range patterns {
+			if re.MatchString(pname) {
+				continue loop
+			}
+		}
+		undefined = append(undefined, pname)
+	}
+	if len(undefined) > 0 {
+		l.report(res, "required", "required properties %s are not defined in properties", quoteAll(undefined))
+	}
+}
+
+func lintUnusedDefs(l *linter, res *resource, m *OrderedMap) {
+	for _, kw := range []string{"$defs", "definitions"} {
+		defs, ok := m.RawValues()[kw].(*OrderedMap)
+		if !ok {
+			continue
+		}
+		for _, name := range defs.Keys() {
+			floc := res.floc + "/" + escape(kw) + "/" + escape(name)
+			def, ok := l.r.subresources[floc]
+			if !ok {
+				continue
+			}
+			targets := []string{unescapeURL(l.r.url + floc)}
+			if def.url != "" {
+				targets = append(targets, def.url, def.url+"#")
+			}
+			for _, anchor := range l.r.draft.anchors(def.doc) {
+				targets = append(targets, l.r.baseURL(floc)+"#"+anchor)
+			}
+			if !l.isReferenced(targets) {
+				l.report(res, escape(kw)+"/"+escape(name), "%s is not referenced", quote(name))
+			}
+		}
+	}
+}
+
+// isReferenced tells whether any of targets, or its subschemas are referenced.
+func (l *linter) isReferenced(targets []string) bool {
+	for _, ref := range l.references() {
+		for _, t := range targets {
+			if ref == t || strings.HasPrefix(ref, t+"/") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func lintUnsatisfiable(l *linter, res *resource, m *OrderedMap) {
+	ints := [][2]string{
+		{"minLength", "maxLength"},
+		{"minItems", "maxItems"},
+		{"minProperties", "maxProperties"},
+		{"minContains", "maxContains"},
+	}
+	for _, kw := range ints {
+		min, max := lintRat(m, kw[0]), lintRat(m, kw[1])
+		if min != nil && max != nil && min.Cmp(max) > 0 {
+			l.report(res, kw[0], "%s %s is greater than %s %s", kw[0], min.RatString(), kw[1], max.RatString())
+		}
+	}
+
+	// bound returns the tighter number bound, and whether it is exclusive.
+	// sign is 1 for lower bound and -1 for upper bound.
+	bound := func(kw, exclusiveKw string, sign int) (string, *big.Rat, bool) {
+		if exclusive, _ := m.Get(exclusiveKw); exclusive == true {
+			return kw, lintRat(m, kw), true
+		}
+		inclusive, exclusive := lintRat(m, kw), lintRat(m, exclusiveKw)
+		if exclusive != nil && (inclusive == nil || exclusive.Cmp(inclusive)*sign >= 0) {
+			return exclusiveKw, exclusive, true
+		}
+		return kw, inclusive, false
+	}
+	minKw, min, minExclusive := bound("minimum", "exclusiveMinimum", 1)
+	maxKw, max, maxExclusive := bound("maximum", "exclusiveMaximum", -1)
+	if min != nil && max != nil {
+		if c := min.Cmp(max); c > 0 || (c == 0 && (minExclusive || maxExclusive)) {
+			l.report(res, minKw, "no number satisfies both %s %s and %s %s", minKw, min.RatString(), maxKw, max.RatString())
+		}
+	}
+}
+
+// lintRat returns the value of number keyword kw in m.
+func lintRat(m *OrderedMap, kw string) *big.Rat {
+	if num, ok := m.RawValues()[kw].(json.Number); ok {
+		if r, ok := new(big.Rat).SetString(string(num)); ok {
+			return r
+		}
+	}
+	return nil
+}
+
+func lintAdditionalPropertiesAllOf(l *linter, res *resource, m *OrderedMap) {
+	if additional, _ := m.Get("additionalProperties"); additional != false {
+		return
+	}
+	var own *OrderedMap
+	if props, ok := m.RawValues()["properties"].(*OrderedMap); ok {
+		own = props
+	}
+	var hidden []string
+	for _, kw := range []string{"allOf", "anyOf", "oneOf"} {
+		branches, ok := m.RawValues()[kw].([]interface{})
+		if !ok {
+			continue
+		}
+		for _, branch := range branches {
+			branch, ok := branch.(*OrderedMap)
+			if !ok {
+				continue
+			}
+			props, ok := branch.RawValues()["properties"].(*OrderedMap)
+			if !ok {
+				continue
+			}
+			for _, pname := range props.Keys() {
+				if own != nil {
+					if _, ok := own.Get(pname); ok {
+						continue
+					}
+				}
+				if !contains(hidden, pname) {
+					hidden = append(hidden, pname)
+				}
+			}
+		}
+	}
+	if len(hidden) == 0 {
+		return
+	}
+	msg := "additionalProperties false rejects properties %s declared only in subschemas"
+	if l.r.draft.version >= 2019 {
+		msg += "; use unevaluatedProperties instead"
+	}
+	l.report(res, "additionalProperties", msg, quoteAll(hidden))
+}
+
+func lintOverlappingPatternProperties(l *linter, res *resource, m *OrderedMap) {
+	pprops, ok := m.RawValues()["patternProperties"].(*OrderedMap)
+	if !ok {
+		return
+	}
+	patterns := pprops.Keys()
+	res2 := make([]*regexp.Regexp, len(patterns))
+	examples := make([]string, len(patterns))
+	for i, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return
+		}
+		res2[i] = re
+		if sre, err := syntax.Parse(pattern, syntax.Perl); err == nil {
+			examples[i] = regexExample(sre.Simplify())
+		}
+	}
+	for i := range patterns {
+		for j := i + 1; j < len(patterns); j++ {
+			if res2[j].MatchString(examples[i]) || res2[i].MatchString(examples[j]) {
+				l.report(res, "patternProperties", "patterns %s and %s can match same property", quote(patterns[i]), quote(patterns[j]))
+			}
+		}
+	}
+}
+
+// regexExample returns a short string matched by re. It is used to find
+// overlapping patterns, so it need not be exact.
+func regexExample(re *syntax.Regexp) string {
+	switch re.Op {
+	case syntax.OpLiteral:
+		return string(re.Rune)
+	case syntax.OpCharClass:
+		if len(re.Rune) > 0 {
+			return string(re.Rune[0])
+		}
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		return "a"
+	case syntax.OpCapture, syntax.OpPlus:
+		return regexExample(re.Sub[0])
+	case syntax.OpRepeat:
+		return strings.Repeat(regexExample(re.Sub[0]), re.Min)
+	case syntax.OpConcat:
+		var sb strings.Builder
+		for _, sub := range re.Sub {
+			sb.WriteString(regexExample(sub))
+		}
+		return sb.String()
+	case syntax.OpAlternate:
+		return regexExample(re.Sub[0])
+	}
+	return ""
+}
@@ -0,0 +1,93 @@
+package jsonschema_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+func TestCompiler_Lint(t *testing.T) {
+	schema := `{
+		"$defs": {
+			"used": {"type": "string"},
+			"unused": {"type": "integer"},
+			"anchored": {"$anchor": "anchored"},
+			"range": {"minimum": 10, "maximum": 5, "minItems": 3, "maxItems": 2},
+			"exclusive": {"exclusiveMinimum": 5, "maximum": 5},
+			"both": {"minimum": 10, "exclusiveMinimum": 0, "maximum": 5}
+		},
+		"type": "object",
+		"properties": {
+			"name": {"$ref": "#/$defs/used"},
+			"ref": {"$ref": "#anchored"}
+		},
+		"required": ["name", "age", "x-id"],
+		"patternProperties": {"^x-": {}, "^x-id$": {}, "^y": {}},
+		"allOf": [{"properties": {"extra": true}}],
+		"additionalProperties": false
+	}`
+	c := jsonschema.NewCompiler()
+	if err := c.AddResource("http://example.com/schema.json", strings.NewReader(schema)); err != nil {
+		t.Fatal(err)
+	}
+	lints, err := c.Lint("http://example.com/schema.json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, lint := range lints {
+		got = append(got, lint.String())
+	}
+	want := []string{
+		"warning: http://example.com/schema.json#: $schema is missing; draft used depends on compiler configuration (missing-schema)",
+		"info: http://example.com/schema.json#: $id is missing; schema is identified by its retrieval url (missing-id)",
+		"warning: http://example.com/schema.json#/$defs/both: 'both' is not referenced (unused-defs)",
+		"error: http://example.com/schema.json#/$defs/both/minimum: no number satisfies both minimum 10 and maximum 5 (unsatisfiable)",
+		"warning: http://example.com/schema.json#/$defs/exclusive: 'exclusive' is not referenced (unused-defs)",
+		"error: http://example.com/schema.json#/$defs/exclusive/exclusiveMinimum: no number satisfies both exclusiveMinimum 5 and maximum 5 (unsatisfiable)",
+		"warning: http://example.com/schema.json#/$defs/range: 'range' is not referenced (unused-defs)",
+		"error: http://example.com/schema.json#/$defs/range/minItems: minItems 3 is greater than maxItems 2 (unsatisfiable)",
+		"error: http://example.com/schema.json#/$defs/range/minimum: no number satisfies both minimum 10 and maximum 5 (unsatisfiable)",
+		"warning: http://example.com/schema.json#/$defs/unused: 'unused' is not referenced (unused-defs)",
+		"warning: http://example.com/schema.json#/additionalProperties: additionalProperties false rejects properties 'extra' declared only in subschemas; use unevaluatedProperties instead (additional-properties-allof)",
+		"info: http://example.com/schema.json#/patternProperties: patterns '^x-' and '^x-id$' can match same property (overlapping-pattern-properties)",
+		"warning: http://example.com/schema.json#/required: required properties 'age' are not defined in properties (required-undefined)",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestCompiler_LintRules(t *testing.T) {
+	var rules []jsonschema.LintRule
+	for _, rule := range jsonschema.DefaultLintRules() {
+		if rule.Name == "missing-id" {
+			continue
+		}
+		if rule.Name == "missing-schema" {
+			rule.Severity = jsonschema.LintError
+		}
+		rules = append(rules, rule)
+	}
+	c := jsonschema.NewCompiler()
+	if err := c.AddResource("schema.json", strings.NewReader(`{"type": "string"}`)); err != nil {
+		t.Fatal(err)
+	}
+	lints, err := c.Lint("schema.json", rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lints) != 1 || lints[0].Rule != "missing-schema" || lints[0].Severity != jsonschema.LintError {
+		t.Errorf("unexpected lints %v", lints)
+	}
+
+	// compilation errors
+	c = jsonschema.NewCompiler()
+	if err := c.AddResource("schema.json", strings.NewReader(`{"type": 1}`)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Lint("schema.json", nil); err == nil {
+		t.Error("want error")
+	}
+}
@@ -0,0 +1,453 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"sort"
+)
+
+// MarshalJSON returns json-schema document equivalent to s.
+//
+// see ToJSON for details.
+func (s *Schema) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.ToJSON())
+}
+
+// ToJSON returns json-schema document equivalent to s. The returned
+// value is either bool or *OrderedMap.
+//
+// The document is constructed from compiled fields, so any changes made
+// to s after compilation are reflected. Subschemas are rendered inline,
+// whereas references are rendered as "$ref" to absolute location of the
+// target schema. Annotations are included only if they were extracted
+// by compiler, see Compiler.ExtractAnnotations.
+//
+// Extensions are included, if their ExtSchema implements json.Marshaler
+// which marshals to json object.
+func (s *Schema) ToJSON() interface{} {
+	m := NewOrderedMap()
+	if s.Always == nil && s.draft != nil && s.draft.url != "" {
+		m.Set("$schema", s.draft.url)
+	}
+	return s.toJSON(m)
+}
+
+func (s *Schema) toJSON(m *OrderedMap) interface{} {
+	if s.Always != nil {
+		return *s.Always
+	}
+	version := 2020
+	if s.draft != nil {
+		version = s.draft.version
+	}
+
+	if s.Ref != nil {
+		m.Set("$ref", s.Ref.Location)
+	}
+	if s.RecursiveAnchor {
+		m.Set("$recursiveAnchor", true)
+	}
+	if s.RecursiveRef != nil {
+		m.Set("$recursiveRef", s.RecursiveRef.Location)
+	}
+	if s.DynamicAnchor != "" {
+		m.Set("$dynamicAnchor", s.DynamicAnchor)
+	}
+	if s.DynamicRef != nil {
+		if s.DynamicRef.DynamicAnchor != "" {
+			m.Set("$dynamicRef", s.DynamicRef.url()+"#"+s.DynamicRef.DynamicAnchor)
+		} else {
+			m.Set("$dynamicRef", s.DynamicRef.Location)
+		}
+	}
+
+	// annotations
+	if s.Title != "" {
+		m.Set("title", s.Title)
+	}
+	if s.Description != "" {
+		m.Set("description", s.Description)
+	}
+	if s.Comment != "" {
+		m.Set("$comment", s.Comment)
+	}
+	if s.Default != nil {
+		m.Set("default", s.Default)
+	}
+	if len(s.Examples) == 1 && s.draft != nil && s.draft.openapi {
+		m.Set("example", s.Examples[0])
+	} else if len(s.Examples) > 0 {
+		m.Set("examples", s.Examples)
+	}
+	if s.ReadOnly {
+		m.Set("readOnly", true)
+	}
+	if s.WriteOnly {
+		m.Set("writeOnly", true)
+	}
+	if s.Deprecated {
+		m.Set("deprecated", true)
+	}
+
+	// type agnostic
+	switch {
+	case len(s.TypeSchemas) > 0:
+		m.Set("type", append(stringsToJSON(s.Types), schemasToJSON(s.TypeSchemas)...))
+	case len(s.Types) == 1:
+		m.Set("type", s.Types[0])
+	case len(s.Types) > 1:
+		m.Set("type", stringsToJSON(s.Types))
+	}
+	if s.Nullable {
+		m.Set("nullable", true)
+	}
+	if len(s.Disallow) > 0 || len(s.DisallowSchemas) > 0 {
+		m.Set("disallow", append(stringsToJSON(s.Disallow), schemasToJSON(s.DisallowSchemas)...))
+	}
+	if len(s.Constant) > 0 {
+		m.Set("const", s.Constant[0])
+	}
+	if len(s.Enum) > 0 {
+		m.Set("enum", s.Enum)
+	}
+	if s.Format != "" {
+		m.Set("format", s.Format)
+	}
+	if s.Not != nil {
+		m.Set("not", s.Not.toJSON(NewOrderedMap()))
+	}
+	if len(s.Extends) > 0 {
+		m.Set("extends", schemasToJSON(s.Extends))
+	}
+	if len(s.AllOf) > 0 {
+		m.Set("allOf", schemasToJSON(s.AllOf))
+	}
+	if len(s.AnyOf) > 0 {
+		m.Set("anyOf", schemasToJSON(s.AnyOf))
+	}
+	if len(s.OneOf) > 0 {
+		m.Set("oneOf", schemasToJSON(s.OneOf))
+	}
+	if s.If != nil {
+		m.Set("if", s.If.toJSON(NewOrderedMap()))
+		if s.Then != nil {
+			m.Set("then", s.Then.toJSON(NewOrderedMap()))
+		}
+		if s.Else != nil {
+			m.Set("else", s.Else.toJSON(NewOrderedMap()))
+		}
+	}
+
+	// object
+	if s.MinProperties != -1 {
+		m.Set("minProperties", s.MinProperties)
+	}
+	if s.MaxProperties != -1 {
+		m.Set("maxProperties", s.MaxProperties)
+	}
+	if len(s.Required) > 0 && version >= 4 {
+		m.Set("required", stringsToJSON(s.Required))
+	}
+	if s.Properties != nil {
+		props := NewOrderedMap()
+		for _, pname := range s.Properties.Keys() {
+			sch, _ := s.Properties.Get(pname)
+			psch := NewOrderedMap()
+			if version < 4 {
+				// draft3: required is boolean in property schema
+				for _, req := range s.Required {
+					if req == pname {
+						psch.Set("required", true)
+					}
+				}
+			}
+			props.Set(pname, sch.(*Schema).toJSON(psch))
+		}
+		m.Set("properties", props)
+	}
+	if s.PropertyNames != nil {
+		m.Set("propertyNames", s.PropertyNames.toJSON(NewOrderedMap()))
+	}
+	if s.RegexProperties {
+		m.Set("regexProperties", true)
+	}
+	if len(s.patterns) > 0 {
+		props := NewOrderedMap()
+		for _, re := range s.patterns {
+			props.Set(re.String(), s.PatternProperties[re].toJSON(NewOrderedMap()))
+		}
+		m.Set("patternProperties", props)
+	}
+	if s.AdditionalProperties != nil {
+		m.Set("additionalProperties", additionalToJSON(s.AdditionalProperties))
+	}
+	if len(s.Dependencies.Keys()) > 0 {
+		deps := NewOrderedMap()
+		for _, pname := range s.Dependencies.Keys() {
+			dvalue, _ := s.Dependencies.Get(pname)
+			switch dvalue := dvalue.(type) {
+			case *Schema:
+				deps.Set(pname, dvalue.toJSON(NewOrderedMap()))
+			case []string:
+				deps.Set(pname, stringsToJSON(dvalue))
+			}
+		}
+		m.Set("dependencies", deps)
+	}
+	if len(s.DependentRequired.Keys()) > 0 {
+		deps := NewOrderedMap()
+		for _, pname := range s.DependentRequired.Keys() {
+			dvalue, _ := s.DependentRequired.Get(pname)
+			deps.Set(pname, stringsToJSON(dvalue.([]string)))
+		}
+		m.Set("dependentRequired", deps)
+	}
+	if len(s.DependentSchemas.Keys()) > 0 {
+		deps := NewOrderedMap()
+		for _, pname := range s.DependentSchemas.Keys() {
+			sch, _ := s.DependentSchemas.Get(pname)
+			deps.Set(pname, sch.(*Schema).toJSON(NewOrderedMap()))
+		}
+		m.Set("dependentSchemas", deps)
+	}
+	if len(s.PropertyDependencies.Keys()) > 0 {
+		deps := NewOrderedMap()
+		for _, pname := range s.PropertyDependencies.Keys() {
+			pvalue, _ := s.PropertyDependencies.Get(pname)
+			schemas := pvalue.(*OrderedMap)
+			values := NewOrderedMap()
+			for _, value := range schemas.Keys() {
+				sch, _ := schemas.Get(value)
+				values.Set(value, sch.(*Schema).toJSON(NewOrderedMap()))
+			}
+			deps.Set(pname, values)
+		}
+		m.Set("propertyDependencies", deps)
+	}
+	if s.Discriminator != nil {
+		d := NewOrderedMap()
+		d.Set("propertyName", s.Discriminator.PropertyName)
+		if len(s.Discriminator.Mapping) > 0 {
+			mapping := NewOrderedMap()
+			for _, value := range sortedKeys(s.Discriminator.Mapping) {
+				mapping.Set(value, s.Discriminator.Mapping[value])
+			}
+			d.Set("mapping", mapping)
+		}
+		m.Set("discriminator", d)
+	}
+	if s.UnevaluatedProperties != nil {
+		m.Set("unevaluatedProperties", s.UnevaluatedProperties.toJSON(NewOrderedMap()))
+	}
+
+	// array
+	if s.MinItems != -1 {
+		m.Set("minItems", s.MinItems)
+	}
+	if s.MaxItems != -1 {
+		m.Set("maxItems", s.MaxItems)
+	}
+	if s.UniqueItems {
+		m.Set("uniqueItems", true)
+	}
+	switch items := s.Items.(type) {
+	case *Schema:
+		m.Set("items", items.toJSON(NewOrderedMap()))
+	case []*Schema:
+		m.Set("items", schemasToJSON(items))
+		if s.AdditionalItems != nil {
+			m.Set("additionalItems", additionalToJSON(s.AdditionalItems))
+		}
+	}
+	if len(s.PrefixItems) > 0 {
+		m.Set("prefixItems", schemasToJSON(s.PrefixItems))
+	}
+	if s.Items2020 != nil {
+		m.Set("items", s.Items2020.toJSON(NewOrderedMap()))
+	}
+	if s.Contains != nil {
+		m.Set("contains", s.Contains.toJSON(NewOrderedMap()))
+		if version >= 2019 {
+			if s.MinContains != 1 {
+				m.Set("minContains", s.MinContains)
+			}
+			if s.MaxContains != -1 {
+				m.Set("maxContains", s.MaxContains)
+			}
+		}
+	}
+	if s.UnevaluatedItems != nil {
+		m.Set("unevaluatedItems", s.UnevaluatedItems.toJSON(NewOrderedMap()))
+	}
+
+	// string
+	if s.MinLength != -1 {
+		m.Set("minLength", s.MinLength)
+	}
+	if s.MaxLength != -1 {
+		m.Set("maxLength", s.MaxLength)
+	}
+	if s.Pattern != nil {
+		m.Set("pattern", s.Pattern.String())
+	}
+	if s.ContentEncoding != "" {
+		m.Set("contentEncoding", s.ContentEncoding)
+	}
+	if s.ContentMediaType != "" {
+		m.Set("contentMediaType", s.ContentMediaType)
+	}
+
+	// number
+	if s.Minimum != nil {
+		m.Set("minimum", ratToJSON(s.Minimum))
+	}
+	if s.ExclusiveMinimum != nil {
+		if version < 6 {
+			m.Set("minimum", ratToJSON(s.ExclusiveMinimum))
+			m.Set("exclusiveMinimum", true)
+		} else {
+			m.Set("exclusiveMinimum", ratToJSON(s.ExclusiveMinimum))
+		}
+	}
+	if s.Maximum != nil {
+		m.Set("maximum", ratToJSON(s.Maximum))
+	}
+	if s.ExclusiveMaximum != nil {
+		if version < 6 {
+			m.Set("maximum", ratToJSON(s.ExclusiveMaximum))
+			m.Set("exclusiveMaximum", true)
+		} else {
+			m.Set("exclusiveMaximum", ratToJSON(s.ExclusiveMaximum))
+		}
+	}
+	if s.MultipleOf != nil {
+		if version < 4 {
+			m.Set("divisibleBy", ratToJSON(s.MultipleOf))
+		} else {
+			m.Set("multipleOf", ratToJSON(s.MultipleOf))
+		}
+	}
+
+	// $data
+	for _, kw := range sortedKeys(s.Data) {
+		ref := NewOrderedMap()
+		ref.Set("$data", s.Data[kw])
+		m.Set(kw, ref)
+		if kw == "minimum" && s.dataExclusiveMinimum {
+			m.Set("exclusiveMinimum", true)
+		}
+		if kw == "maximum" && s.dataExclusiveMaximum {
+			m.Set("exclusiveMaximum", true)
+		}
+	}
+
+	if em := s.ErrorMessage; em != nil {
+		if em.Message != "" {
+			m.Set("errorMessage", em.Message)
+		} else {
+			messages := func(msgs map[string]string) *OrderedMap {
+				m := NewOrderedMap()
+				for _, k := range sortedKeys(msgs) {
+					m.Set(k, msgs[k])
+				}
+				return m
+			}
+			msgs := messages(em.Keywords)
+			if em.Required != nil {
+				msgs.Set("required", messages(em.Required))
+			}
+			if em.Properties != nil {
+				msgs.Set("properties", messages(em.Properties))
+			}
+			m.Set("errorMessage", msgs)
+		}
+	}
+
+	// extensions
+	for _, name := range sortedKeys(s.Extensions) {
+		marshaler, ok := s.Extensions[name].(json.Marshaler)
+		if !ok {
+			continue
+		}
+		b, err := marshaler.MarshalJSON()
+		if err != nil {
+			continue
+		}
+		if doc, err := unmarshal(bytes.NewReader(b)); err == nil {
+			if doc, ok := doc.(*OrderedMap); ok {
+				for _, k := range doc.Keys() {
+					v, _ := doc.Get(k)
+					m.Set(k, v)
+				}
+			}
+		}
+	}
+
+	return m
+}
+
+func schemasToJSON(schemas []*Schema) []interface{} {
+	arr := make([]interface{}, len(schemas))
+	for i, sch := range schemas {
+		arr[i] = sch.toJSON(NewOrderedMap())
+	}
+	return arr
+}
+
+func stringsToJSON(s []string) []interface{} {
+	arr := make([]interface{}, len(s))
+	for i, v := range s {
+		arr[i] = v
+	}
+	return arr
+}
+
+// additionalToJSON converts value of additionalProperties/additionalItems.
+func additionalToJSON(v interface{}) interface{} {
+	if sch, ok := v.(*Schema); ok {
+		return sch.toJSON(NewOrderedMap())
+	}
+	return v
+}
+
+// ratToJSON converts r to json number, using exact decimal
+// representation whenever possible.
+func ratToJSON(r *big.Rat) json.Number {
+	if r.IsInt() {
+		return json.Number(r.Num().String())
+	}
+	for prec := 1; prec <= 64; prec++ {
+		s := r.FloatString(prec)
+		if f, ok := new(big.Rat).SetString(s); ok && f.Cmp(r) == 0 {
+			return json.Number(s)
+		}
+	}
+	f, _ := r.Float64()
+	return json.Number(big.NewFloat(f).Text('g', -1))
+}
+
+// sortedKeys returns keys of given map in sorted order. m must be a map with string keys.
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	switch m := m.(type) {
+	case map[string]*Schema:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	case map[string]interface{}:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	case map[string]string:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	case map[string]ExtSchema:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,184 @@
+package jsonschema_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+func TestSchema_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		draft     *jsonschema.Draft
+		schema    string
+		instances []string
+	}{
+		{
+			jsonschema.Draft2020,
+			`{
+				"$defs": {"positive": {"type": "number", "exclusiveMinimum": 0}},
+				"type": "object",
+				"properties": {
+					"name": {"type": "string", "minLength": 1, "pattern": "^[a-z]+$"},
+					"price": {"$ref": "#/$defs/positive", "multipleOf": 0.01},
+					"tags": {"type": "array", "prefixItems": [{"const": "x"}], "items": {"enum": ["a", "b"]}, "uniqueItems": true}
+				},
+				"patternProperties": {"^x-": {"type": "integer"}},
+				"additionalProperties": false,
+				"required": ["name"],
+				"dependentRequired": {"price": ["name"]}
+			}`,
+			[]string{
+				`{"name": "apple", "price": 1.25}`,
+				`{"name": "apple", "price": 1.255}`,
+				`{"name": "apple", "price": -1}`,
+				`{"name": "Apple"}`,
+				`{"name": "apple", "x-id": 1, "tags": ["x", "a", "b"]}`,
+				`{"name": "apple", "x-id": 1.5}`,
+				`{"name": "apple", "tags": ["y"]}`,
+				`{"name": "apple", "other": 1}`,
+				`{"price": 1}`,
+				`"apple"`,
+			},
+		},
+		{
+			jsonschema.Draft4,
+			`{
+				"definitions": {"small": {"maximum": 10, "exclusiveMaximum": true}},
+				"items": [{"$ref": "#/definitions/small"}, {"type": "string"}],
+				"additionalItems": false,
+				"dependencies": {"a": ["b"], "c": {"required": ["d"]}}
+			}`,
+			[]string{
+				`[9, "a"]`,
+				`[10, "a"]`,
+				`[9, "a", true]`,
+				`{"a": 1}`,
+				`{"a": 1, "b": 2}`,
+				`{"c": 1}`,
+			},
+		},
+		{
+			jsonschema.Draft3,
+			`{
+				"type": ["string", {"type": "integer", "divisibleBy": 2}],
+				"extends": {"minLength": 2},
+				"disallow": [{"enum": ["xx"]}],
+				"properties": {"a": {"type": "any", "required": true}}
+			}`,
+			[]string{
+				`"ab"`,
+				`"a"`,
+				`"xx"`,
+				`4`,
+				`3`,
+				`{"a": 1}`,
+				`{}`,
+			},
+		},
+		{
+			jsonschema.OpenAPI30,
+			`{
+				"type": "object",
+				"properties": {
+					"kind": {"type": "string"},
+					"name": {"type": "string", "nullable": true}
+				},
+				"discriminator": {"propertyName": "kind", "mapping": {"a": "#/a"}}
+			}`,
+			[]string{
+				`{"kind": "a", "name": "x"}`,
+				`{"kind": "a", "name": null}`,
+				`{"kind": "a", "name": 1}`,
+				`{"name": "x"}`,
+			},
+		},
+	}
+	for i, test := range tests {
+		c := jsonschema.NewCompiler()
+		c.Draft = test.draft
+		if err := c.AddResource("schema.json", strings.NewReader(test.schema)); err != nil {
+			t.Fatal(err)
+		}
+		sch, err := c.Compile("schema.json")
+		if err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+		b, err := json.Marshal(sch)
+		if err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+		t.Logf("#%d: %s", i, b)
+		if err := c.AddResource("marshalled.json", bytes.NewReader(b)); err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+		msch, err := c.Compile("marshalled.json")
+		if err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+		for _, instance := range test.instances {
+			want := sch.Validate(decodeString(t, instance)) == nil
+			got := msch.Validate(decodeString(t, instance)) == nil
+			if got != want {
+				t.Errorf("#%d: %s: got %v, want %v", i, instance, got, want)
+			}
+		}
+	}
+}
+
+func TestSchema_ToJSON(t *testing.T) {
+	sch, err := jsonschema.CompileString("schema.json", `{
+		"properties": {"a": {"$ref": "#/$defs/a"}, "b": false},
+		"$defs": {"a": {"type": ["string", "null"]}}
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doc := sch.ToJSON().(*jsonschema.OrderedMap)
+	if v, _ := doc.Get("$schema"); v != "https://json-schema.org/draft/2020-12/schema" {
+		t.Errorf("$schema: got %v", v)
+	}
+	props, _ := doc.Get("properties")
+	a, _ := props.(*jsonschema.OrderedMap).Get("a")
+	ref, _ := a.(*jsonschema.OrderedMap).Get("$ref")
+	if !strings.HasSuffix(ref.(string), "/schema.json#/$defs/a") {
+		t.Errorf("$ref: got %v", ref)
+	}
+	if b, _ := props.(*jsonschema.OrderedMap).Get("b"); b != false {
+		t.Errorf("b: got %v, want false", b)
+	}
+
+	// subschemas can be marshalled too
+	target := sch.Properties.RawValues()["a"].(*jsonschema.Schema).Ref
+	b, err := json.Marshal(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"$schema":"https://json-schema.org/draft/2020-12/schema","type":["string","null"]}`; string(b) != want {
+		t.Errorf("got %s, want %s", b, want)
+	}
+}
+
+func TestSchema_MarshalPropertyDependencies(t *testing.T) {
+	c := jsonschema.NewCompiler()
+	c.Draft = jsonschema.DraftNext
+	schema := `{"propertyDependencies": {"kind": {"z": {"required": ["a"]}, "b": {"required": ["c"]}}}}`
+	if err := c.AddResource("schema.json", strings.NewReader(schema)); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.Compile("schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.Marshal(sch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"$schema":"https://json-schema.org/draft/future/schema","propertyDependencies":{"kind":{"z":{"required":["a"]},"b":{"required":["c"]}}}}`
+	if string(b) != want {
+		t.Errorf("got %s, want %s", b, want)
+	}
+}
@@ -0,0 +1,198 @@
+package jsonschema
+
+import "fmt"
+
+// A Translator translates validation error messages.
+//
+// id identifies the message, and a are its parameters. Message ids and
+// the order of their parameters are listed in English catalog. Set
+// Compiler.Translator to use a Translator.
+type Translator interface {
+	Translate(id string, a ...interface{}) string
+}
+
+// A Catalog is a Translator, which maps message id to fmt format string.
+//
+// Format strings may use explicit argument indexes such as %[2]s, to
+// reorder or omit the parameters. Messages missing in catalog are taken
+// from English.
+type Catalog map[string]string
+
+// Translate implements Translator.
+func (c Catalog) Translate(id string, a ...interface{}) string {
+	format, ok := c[id]
+	if !ok {
+		format = English[id]
+	}
+	return fmt.Sprintf(format, a...)
+}
+
+// Catalogs is the registered catalogs, keyed by language tag. The catalog
+// for a locale can be selected as follows:
+//
+//	compiler.Translator = jsonschema.Catalogs["de"]
+//
+// Catalogs for other languages can be registered here.
+var Catalogs = map[string]Catalog{
+	"en": English,
+	"de": German,
+	"ja": Japanese,
+}
+
+func translate(t Translator, id string, a ...interface{}) string {
+	if t == nil {
+		return English.Translate(id, a...)
+	}
+	return t.Translate(id, a...)
+}
+
+// English is the default catalog. The comment against each message id
+// lists its parameters.
+var English = Catalog{
+	"schema":               "doesn't validate with %s",                                          // schema url
+	"false":                "not allowed",                                                       // none
+	"type":                 "expected %s, but got %s",                                           // allowed types joined with "or" message, instance type
+	"or":                   " or ",                                                              // none
+	"disallow":             "%s is not allowed",                                                 // instance type
+	"const":                "const failed",                                                      // none
+	"constValue":           "value must be %#v",                                                 // const value
+	"enum":                 "enum failed",                                                       // none
+	"enumValue":            "value must be %s",                                                  // enum value
+	"enumValues":           "value must be one of %s",                                           // enum values joined with ", "
+	"format":               "%v is not valid %s",                                                // instance, quoted format
+	"minContains":          "valid must be >= %d, but got %d",                                   // minContains, matched items
+	"maxContains":          "valid must be <= %d, but got %d",                                   // maxContains, matched items
+	"minProperties":        "minimum %d properties allowed, but found %d properties",            // minProperties, instance properties
+	"maxProperties":        "maximum %d properties allowed, but found %d properties",            // maxProperties, instance properties
+	"required":             "missing properties: %s",                                            // quoted properties joined with ", "
+	"discriminatorMissing": "missing discriminator property %s",                                 // quoted property
+	"discriminatorType":    "discriminator property %s must be string, but got %s",              // quoted property, property type
+	"discriminatorValue":   "unknown value %s for discriminator property %s, must be one of %s", // quoted value, quoted property, quoted values joined with ", "
+	"regexProperty":        "patternProperty %s is not valid regex",                             // quoted property
+	"additionalProperties": "additionalProperties %s not allowed",                               // quoted properties joined with ", "
+	"dependentRequired":    "property %s is required, if %s property exists",                    // quoted property, quoted dependent property
+	"minItems":             "minimum %d items required, but found %d items",                     // minItems, instance items
+	"maxItems":             "maximum %d items required, but found %d items",                     // maxItems, instance items
+	"uniqueItems":          "items at index %d and %d are equal",                                // index, index
+	"additionalItems":      "only %d items are allowed, but found %d items",                     // allowed items, instance items
+	"minLength":            "length must be >= %d, but got %d",                                  // minLength, instance length
+	"maxLength":            "length must be <= %d, but got %d",                                  // maxLength, instance length
+	"pattern":              "does not match pattern %s",                                         // quoted pattern
+	"contentEncoding":      "%s is not %s encoded",                                              // quoted instance, contentEncoding
+	"contentMediaType":     "value is not of mediatype %s",                                      // quoted contentMediaType
+	"minimum":              "must be >= %v but found %v",                                        // minimum, instance
+	"exclusiveMinimum":     "must be > %v but found %v",                                         // exclusiveMinimum, instance
+	"maximum":              "must be <= %v but found %v",                                        // maximum, instance
+	"exclusiveMaximum":     "must be < %v but found %v",                                         // exclusiveMaximum, instance
+	"divisibleBy":          "%v not divisibleBy %v",                                             // instance, divisibleBy
+	"multipleOf":           "%v not multipleOf %v",                                              // instance, multipleOf
+	"not":                  "not failed",                                                        // none
+	"extends":              "extends failed",                                                    // none
+	"allOf":                "allOf failed",                                                      // none
+	"anyOf":                "anyOf failed",                                                      // none
+	"oneOf":                "oneOf failed",                                                      // none
+	"oneOfMultiple":        "valid against schemas at indexes %d and %d",                        // index, index
+	"then":                 "if-then failed",                                                    // none
+	"else":                 "if-else failed",                                                    // none
+	"data":                 "$data %s must be %s, but got %v",                                   // quoted $data, expected value, referenced value
+}
+
+// German catalog.
+var German = Catalog{
+	"schema":               "validiert nicht gegen %s",
+	"false":                "nicht erlaubt",
+	"type":                 "%s erwartet, aber %s erhalten",
+	"or":                   " oder ",
+	"disallow":             "%s ist nicht erlaubt",
+	"const":                "const fehlgeschlagen",
+	"constValue":           "Wert muss %#v sein",
+	"enum":                 "enum fehlgeschlagen",
+	"enumValue":            "Wert muss %s sein",
+	"enumValues":           "Wert muss einer von %s sein",
+	"format":               "%v ist kein gültiges %s",
+	"minContains":          "Anzahl gültiger Elemente muss >= %d sein, aber ist %d",
+	"maxContains":          "Anzahl gültiger Elemente muss <= %d sein, aber ist %d",
+	"minProperties":        "mindestens %d Eigenschaften erforderlich, aber %d gefunden",
+	"maxProperties":        "höchstens %d Eigenschaften erlaubt, aber %d gefunden",
+	"required":             "fehlende Eigenschaften: %s",
+	"discriminatorMissing": "fehlende Diskriminator-Eigenschaft %s",
+	"discriminatorType":    "Diskriminator-Eigenschaft %s muss ein String sein, aber ist %s",
+	"discriminatorValue":   "unbekannter Wert %s für Diskriminator-Eigenschaft %s, muss einer von %s sein",
+	"regexProperty":        "Eigenschaft %s ist kein gültiger regulärer Ausdruck",
+	"additionalProperties": "zusätzliche Eigenschaften %s nicht erlaubt",
+	"dependentRequired":    "Eigenschaft %s ist erforderlich, wenn Eigenschaft %s existiert",
+	"minItems":             "mindestens %d Elemente erforderlich, aber %d gefunden",
+	"maxItems":             "höchstens %d Elemente erlaubt, aber %d gefunden",
+	"uniqueItems":          "Elemente an Index %d und %d sind gleich",
+	"additionalItems":      "nur %d Elemente erlaubt, aber %d gefunden",
+	"minLength":            "Länge muss >= %d sein, aber ist %d",
+	"maxLength":            "Länge muss <= %d sein, aber ist %d",
+	"pattern":              "entspricht nicht dem Muster %s",
+	"contentEncoding":      "%s ist nicht %s-kodiert",
+	"contentMediaType":     "Wert ist nicht vom Medientyp %s",
+	"minimum":              "muss >= %v sein, aber ist %v",
+	"exclusiveMinimum":     "muss > %v sein, aber ist %v",
+	"maximum":              "muss <= %v sein, aber ist %v",
+	"exclusiveMaximum":     "muss < %v sein, aber ist %v",
+	"divisibleBy":          "%v ist nicht durch %v teilbar",
+	"multipleOf":           "%v ist kein Vielfaches von %v",
+	"not":                  "not fehlgeschlagen",
+	"extends":              "extends fehlgeschlagen",
+	"allOf":                "allOf fehlgeschlagen",
+	"anyOf":                "anyOf fehlgeschlagen",
+	"oneOf":                "oneOf fehlgeschlagen",
+	"oneOfMultiple":        "gültig gegen Schemas an Index %d und %d",
+	"then":                 "if-then fehlgeschlagen",
+	"else":                 "if-else fehlgeschlagen",
+	"data":                 "$data %s muss %s sein, aber %v erhalten",
+}
+
+// Japanese catalog.
+var Japanese = Catalog{
+	"schema":               "%s に対して検証できません",
+	"false":                "許可されていません",
+	"type":                 "%s が必要ですが、%s でした",
+	"or":                   " または ",
+	"disallow":             "%s は許可されていません",
+	"const":                "const の検証に失敗しました",
+	"constValue":           "値は %#v でなければなりません",
+	"enum":                 "enum の検証に失敗しました",
+	"enumValue":            "値は %s でなければなりません",
+	"enumValues":           "値は %s のいずれかでなければなりません",
+	"format":               "%v は有効な %s ではありません",
+	"minContains":          "有効な要素は %d 個以上必要ですが、%d 個でした",
+	"maxContains":          "有効な要素は %d 個以下でなければなりませんが、%d 個でした",
+	"minProperties":        "プロパティは %d 個以上必要ですが、%d 個でした",
+	"maxProperties":        "プロパティは %d 個以下でなければなりませんが、%d 個でした",
+	"required":             "プロパティがありません: %s",
+	"discriminatorMissing": "識別子プロパティ %s がありません",
+	"discriminatorType":    "識別子プロパティ %s は文字列でなければなりませんが、%s でした",
+	"discriminatorValue":   "識別子プロパティ %[2]s の値 %[1]s は不明です。%[3]s のいずれかでなければなりません",
+	"regexProperty":        "プロパティ %s は有効な正規表現ではありません",
+	"additionalProperties": "追加プロパティ %s は許可されていません",
+	"dependentRequired":    "プロパティ %[2]s が存在する場合、プロパティ %[1]s は必須です",
+	"minItems":             "要素は %d 個以上必要ですが、%d 個でした",
+	"maxItems":             "要素は %d 個以下でなければなりませんが、%d 個でした",
+	"uniqueItems":          "インデックス %d と %d の要素が等しいです",
+	"additionalItems":      "要素は %d 個までですが、%d 個でした",
+	"minLength":            "長さは %d 以上でなければなりませんが、%d でした",
+	"maxLength":            "長さは %d 以下でなければなりませんが、%d でした",
+	"pattern":              "パターン %s に一致しません",
+	"contentEncoding":      "%s は %s エンコードされていません",
+	"contentMediaType":     "値はメディアタイプ %s ではありません",
+	"minimum":              "%v 以上でなければなりませんが、%v でした",
+	"exclusiveMinimum":     "%v より大きくなければなりませんが、%v でした",
+	"maximum":              "%v 以下でなければなりませんが、%v でした",
+	"exclusiveMaximum":     "%v より小さくなければなりませんが、%v でした",
+	"divisibleBy":          "%[1]v は %[2]v で割り切れません",
+	"multipleOf":           "%[1]v は %[2]v の倍数ではありません",
+	"not":                  "not の検証に失敗しました",
+	"extends":              "extends の検証に失敗しました",
+	"allOf":                "allOf の検証に失敗しました",
+	"anyOf":                "anyOf の検証に失敗しました",
+	"oneOf":                "oneOf の検証に失敗しました",
+	"oneOfMultiple":        "インデックス %d と %d のスキーマの両方に対して有効です",
+	"then":                 "if-then の検証に失敗しました",
+	"else":                 "if-else の検証に失敗しました",
+	"data":                 "$data %s は %s でなければなりませんが、%v でした",
+}
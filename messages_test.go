@@ -0,0 +1,120 @@
+package jsonschema_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+func TestCatalogs(t *testing.T) {
+	for lang, catalog := range jsonschema.Catalogs {
+		for id, format := range catalog {
+			english, ok := jsonschema.English[id]
+			if !ok {
+				t.Errorf("%s: unknown message id %q", lang, id)
+				continue
+			}
+			if n, m := strings.Count(english, "%"), strings.Count(format, "%"); n != m {
+				t.Errorf("%s: %q has %d parameters, want %d", lang, id, m, n)
+			}
+			a := make([]interface{}, strings.Count(english, "%"))
+			for i := range a {
+				a[i] = 1
+			}
+			if msg := catalog.Translate(id, a...); strings.Contains(msg, "%!(") {
+				t.Errorf("%s: %q: %s", lang, id, msg)
+			}
+		}
+	}
+}
+
+type upperTranslator struct{}
+
+func (upperTranslator) Translate(id string, a ...interface{}) string {
+	return strings.ToUpper(jsonschema.English.Translate(id, a...))
+}
+
+func TestTranslator(t *testing.T) {
+	tests := []struct {
+		description string
+		translator  jsonschema.Translator
+		schema      string
+		doc         string
+		messages    []string
+	}{
+		{
+			description: "default",
+			schema:      `{"type": ["string", "null"]}`,
+			doc:         `1`,
+			messages:    []string{"expected string or null, but got number"},
+		},
+		{
+			description: "german",
+			translator:  jsonschema.Catalogs["de"],
+			schema:      `{"type": ["string", "null"]}`,
+			doc:         `1`,
+			messages:    []string{"string oder null erwartet, aber number erhalten"},
+		},
+		{
+			description: "japanese reordered",
+			translator:  jsonschema.Catalogs["ja"],
+			schema:      `{"dependentRequired": {"a": ["b"]}}`,
+			doc:         `{"a": 1}`,
+			messages:    []string{"プロパティ 'a' が存在する場合、プロパティ 'b' は必須です"},
+		},
+		{
+			description: "enum",
+			translator:  jsonschema.German,
+			schema:      `{"enum": [1, 2]}`,
+			doc:         `3`,
+			messages:    []string{`Wert muss einer von "1", "2" sein`},
+		},
+		{
+			description: "fallback to english",
+			translator:  jsonschema.Catalog{"minLength": "mindestens %[1]d Zeichen"},
+			schema:      `{"minLength": 2, "maxLength": 0}`,
+			doc:         `"a"`,
+			messages:    []string{"mindestens 2 Zeichen", "length must be <= 0, but got 1"},
+		},
+		{
+			description: "custom translator",
+			translator:  upperTranslator{},
+			schema:      `{"required": ["a"]}`,
+			doc:         `{}`,
+			messages:    []string{"MISSING PROPERTIES: 'A'"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			c := jsonschema.NewCompiler()
+			c.Translator = test.translator
+			if err := c.AddResource("schema.json", strings.NewReader(test.schema)); err != nil {
+				t.Fatal(err)
+			}
+			sch, err := c.Compile("schema.json")
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = sch.Validate(decodeString(t, test.doc))
+			verr, ok := err.(*jsonschema.ValidationError)
+			if !ok {
+				t.Fatalf("got %v, want *ValidationError", err)
+			}
+			var messages []string
+			var collect func(ve *jsonschema.ValidationError)
+			collect = func(ve *jsonschema.ValidationError) {
+				if len(ve.Causes) == 0 {
+					messages = append(messages, ve.Message)
+				}
+				for _, c := range ve.Causes {
+					collect(c)
+				}
+			}
+			collect(verr)
+			if strings.Join(messages, "\n") != strings.Join(test.messages, "\n") {
+				t.Errorf("got %q, want %q", messages, test.messages)
+			}
+		})
+	}
+}
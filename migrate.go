@@ -0,0 +1,511 @@
+package jsonschema
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Migrate returns copy of schema document doc, rewritten from draft from
+// to draft to. from is used only if doc does not have "$schema" attribute.
+// doc must be either bool or *OrderedMap, as returned by Bundle etc.
+//
+// The following rewrites are done, preserving order of keywords:
+//
+//	required: true in properties  -> required
+//	extends                       -> allOf
+//	type, disallow with schemas   -> anyOf, not
+//	divisibleBy                   -> multipleOf
+//	id                            -> $id
+//	id with only fragment         -> $anchor (draft2019-09 onwards)
+//	boolean exclusiveMinimum/Max  -> numeric exclusiveMinimum/Max
+//	definitions                   -> $defs
+//	dependencies                  -> dependentRequired, dependentSchemas
+//	items array, additionalItems  -> prefixItems, items
+//	$recursiveRef, $recursiveAnchor -> $dynamicRef, $dynamicAnchor
+//
+// Keywords next to "$ref" are dropped when migrating to draft2019-09 or
+// later, because they were ignored in earlier drafts. Local references
+// are rewritten to the new locations of their target schemas. Non-local
+// references are rewritten only for "definitions".
+//
+// Migrating to older draft is not supported. The returned document is
+// validated against the meta-schema of draft to.
+func Migrate(doc interface{}, from, to *Draft) (interface{}, error) {
+	if m, ok := doc.(*OrderedMap); ok {
+		if d, err := schemaDraft(m); err != nil {
+			return nil, err
+		} else if d != nil {
+			from = d
+		}
+	}
+	if from.version > to.version {
+		return nil, fmt.Errorf("jsonschema: cannot migrate from %s to older draft %s", from.url, to.url)
+	}
+
+	mg := &migrator{to: to, locs: make(map[string]string), recursive: make(map[string]bool)}
+	result, err := mg.schema(doc, from, "#", "#", "#", "#")
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range mg.refs {
+		mg.rewriteRef(r)
+	}
+	if m, ok := result.(*OrderedMap); ok {
+		if _, ok := m.Get("$schema"); !ok {
+			sch := NewOrderedMap()
+			sch.Set("$schema", to.url)
+			for _, k := range m.Keys() {
+				v, _ := m.Get(k)
+				sch.Set(k, v)
+			}
+			result = sch
+		}
+	}
+	if err := to.meta.Validate(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// schemaDraft returns the draft specified by "$schema" in m.
+func schemaDraft(m *OrderedMap) (*Draft, error) {
+	v, ok := m.Get("$schema")
+	if !ok {
+		return nil, nil
+	}
+	url, ok := v.(string)
+	if !ok {
+		return nil, nil
+	}
+	d := findDraft(url)
+	if d == nil {
+		return nil, fmt.Errorf("jsonschema: unknown $schema %q", url)
+	}
+	return d, nil
+}
+
+type migrator struct {
+	to        *Draft
+	locs      map[string]string // old location to new location, of schemas
+	recursive map[string]bool   // resources with "$recursiveAnchor": true
+	refs      []migratedRef
+}
+
+// migratedRef is a reference found in migrated document.
+type migratedRef struct {
+	m   *OrderedMap // migrated schema containing the reference
+	kw  string      // keyword holding the reference
+	res string      // old location of resource containing the reference
+}
+
+func (mg *migrator) rewriteRef(r migratedRef) {
+	v, _ := r.m.Get(r.kw)
+	ref, ok := v.(string)
+	if !ok {
+		return
+	}
+	i := strings.IndexByte(ref, '#')
+	if i == -1 {
+		return
+	}
+	u, f := ref[:i], ref[i:]
+	if r.kw == "$dynamicRef" && ref == "#meta" && !mg.recursive[r.res] {
+		// "$recursiveRef" without "$recursiveAnchor" behaves like "$ref"
+		r.m.Delete(r.kw)
+		r.m.Set("$ref", "#")
+		return
+	}
+	if u == "" {
+		if loc, ok := mg.locs[r.res+" "+f]; ok {
+			r.m.Set(r.kw, loc)
+			return
+		}
+	}
+	if mg.to.version >= 2019 && strings.HasPrefix(f, "#/definitions/") {
+		r.m.Set(r.kw, u+"#/$defs/"+strings.TrimPrefix(f, "#/definitions/"))
+	}
+}
+
+// schema migrates schema v at location absLoc in the source document. res
+// is location of the resource containing v. loc and newLoc are locations of
+// v relative to that resource in source and migrated document respectively.
+func (mg *migrator) schema(v interface{}, from *Draft, res, absLoc, loc, newLoc string) (interface{}, error) {
+	m, ok := v.(*OrderedMap)
+	if !ok {
+		return v, nil
+	}
+	if d, err := schemaDraft(m); err != nil {
+		return nil, err
+	} else if d != nil {
+		from = d
+	}
+	to := mg.to
+
+	if id := from.getID(m); id != "" && !strings.HasPrefix(id, "#") {
+		// new schema resource
+		res, loc, newLoc = absLoc, "#", "#"
+	}
+	mg.locs[res+" "+loc] = newLoc
+
+	keys := m.Keys()
+	if _, ok := m.Get("$ref"); ok && from.version < 2019 && to.version >= 2019 {
+		// keywords next to $ref were ignored
+		keys = nil
+		for _, k := range m.Keys() {
+			switch k {
+			case "$ref", "$schema", "definitions", "$comment":
+				keys = append(keys, k)
+			}
+		}
+	}
+
+	result := NewOrderedMap()
+	sub := func(kw string, v interface{}, newKw string) (interface{}, error) {
+		return mg.schema(v, from, res, absLoc+"/"+escape(kw), loc+"/"+escape(kw), newLoc+"/"+escape(newKw))
+	}
+	subs := func(kw string, v interface{}, newKw string) error {
+		arr, ok := v.([]interface{})
+		if !ok {
+			result.Set(newKw, v)
+			return nil
+		}
+		newArr := make([]interface{}, len(arr))
+		for i, item := range arr {
+			p := "/" + strconv.Itoa(i)
+			item, err := mg.schema(item, from, res, absLoc+"/"+kw+p, loc+"/"+kw+p, newLoc+"/"+newKw+p)
+			if err != nil {
+				return err
+			}
+			newArr[i] = item
+		}
+		result.Set(newKw, newArr)
+		return nil
+	}
+	props := func(kw string, v interface{}, newKw string, filter func(interface{}) bool) (*OrderedMap, error) {
+		pm, ok := v.(*OrderedMap)
+		if !ok {
+			return nil, nil
+		}
+		newPM := NewOrderedMap()
+		for _, pname := range pm.Keys() {
+			pv, _ := pm.Get(pname)
+			if filter != nil && !filter(pv) {
+				continue
+			}
+			p := "/" + escape(pname)
+			pv, err := mg.schema(pv, from, res, absLoc+"/"+escape(kw)+p, loc+"/"+escape(kw)+p, newLoc+"/"+escape(newKw)+p)
+			if err != nil {
+				return nil, err
+			}
+			newPM.Set(pname, pv)
+		}
+		return newPM, nil
+	}
+	setProps := func(kw string, v interface{}, newKw string) error {
+		pm, err := props(kw, v, newKw, nil)
+		if err != nil {
+			return err
+		}
+		if pm == nil {
+			result.Set(newKw, v)
+		} else {
+			result.Set(newKw, pm)
+		}
+		return nil
+	}
+	subAt := func(path string, v interface{}, newPath string) (interface{}, error) {
+		return mg.schema(v, from, res, absLoc+"/"+path, loc+"/"+path, newLoc+"/"+newPath)
+	}
+	get := func(kw string) interface{} {
+		v, _ := m.Get(kw)
+		return v
+	}
+
+	for _, kw := range keys {
+		v, _ := m.Get(kw)
+		switch kw {
+		case "$schema":
+			result.Set(kw, to.url)
+		case from.id:
+			id, ok := v.(string)
+			if !ok {
+				result.Set(to.id, v)
+				break
+			}
+			if to.version >= 2019 {
+				if i := strings.IndexByte(id, '#'); i != -1 {
+					if id[:i] != "" {
+						result.Set(to.id, id[:i])
+					}
+					if anchor := id[i+1:]; anchor != "" {
+						result.Set("$anchor", anchor)
+					}
+					break
+				}
+			}
+			result.Set(to.id, id)
+		case "minimum", "maximum":
+			ex := "exclusiveM" + kw[1:]
+			if from.version < 6 && to.version >= 6 && get(ex) == true {
+				result.Set(ex, v)
+			} else {
+				result.Set(kw, v)
+			}
+		case "exclusiveMinimum", "exclusiveMaximum":
+			if _, ok := v.(bool); !ok || from.version >= 6 || to.version < 6 {
+				result.Set(kw, v)
+			}
+		case "definitions", "$defs":
+			newKw := kw
+			if to.version >= 2019 {
+				newKw = "$defs"
+			}
+			if err := setProps(kw, v, newKw); err != nil {
+				return nil, err
+			}
+		case "properties", "patternProperties", "dependentSchemas":
+			if err := setProps(kw, v, kw); err != nil {
+				return nil, err
+			}
+			if pm, ok := v.(*OrderedMap); ok && kw == "properties" && from.version < 4 && to.version >= 4 {
+				// draft3: required is boolean in property schema
+				var required []interface{}
+				for _, pname := range pm.Keys() {
+					if psch, ok := pm.RawValues()[pname].(*OrderedMap); ok {
+						if req, _ := psch.Get("required"); req == true {
+							required = append(required, pname)
+						}
+					}
+				}
+				if len(required) > 0 {
+					result.Set("required", required)
+				}
+			}
+		case "required":
+			if _, ok := v.(bool); !ok || to.version < 4 {
+				result.Set(kw, v)
+			}
+		case "divisibleBy":
+			if from.version < 4 && to.version >= 4 {
+				result.Set("multipleOf", v)
+			} else {
+				result.Set(kw, v)
+			}
+		case "extends":
+			if from.version >= 4 || to.version < 4 {
+				if _, ok := v.([]interface{}); ok {
+					if err := subs(kw, v, kw); err != nil {
+						return nil, err
+					}
+				} else if item, err := sub(kw, v, kw); err != nil {
+					return nil, err
+				} else {
+					result.Set(kw, item)
+				}
+				break
+			}
+			if _, ok := v.([]interface{}); ok {
+				if err := subs(kw, v, "allOf"); err != nil {
+					return nil, err
+				}
+			} else {
+				item, err := subAt(kw, v, "allOf/0")
+				if err != nil {
+					return nil, err
+				}
+				result.Set("allOf", []interface{}{item})
+			}
+		case "type", "disallow":
+			if from.version >= 4 || to.version < 4 {
+				if err := subs(kw, v, kw); err != nil {
+					return nil, err
+				}
+				break
+			}
+			// draft3: union types with schemas
+			types, ok := v.([]interface{})
+			if !ok {
+				types = []interface{}{v}
+			}
+			var names []interface{}
+			any := false
+			for _, t := range types {
+				if t, ok := t.(string); ok {
+					names = append(names, t)
+					any = any || t == "any"
+				}
+			}
+			prefix := "anyOf/"
+			if kw == "disallow" {
+				prefix = "not/anyOf/"
+			}
+			var schemas []interface{}
+			for i, t := range types {
+				if _, ok := t.(string); ok {
+					continue
+				}
+				n := len(schemas)
+				if len(names) > 0 {
+					// first one is for type names
+					n++
+				}
+				item, err := subAt(kw+"/"+strconv.Itoa(i), t, prefix+strconv.Itoa(n))
+				if err != nil {
+					return nil, err
+				}
+				schemas = append(schemas, item)
+			}
+			var typeSch interface{}
+			switch {
+			case any:
+				typeSch = NewOrderedMap()
+			case len(schemas) == 0:
+				m := NewOrderedMap()
+				if len(names) == 1 {
+					m.Set("type", names[0])
+				} else {
+					m.Set("type", names)
+				}
+				typeSch = m
+			default:
+				m := NewOrderedMap()
+				var arr []interface{}
+				if len(names) > 0 {
+					t := NewOrderedMap()
+					t.Set("type", names)
+					arr = append(arr, t)
+				}
+				m.Set("anyOf", append(arr, schemas...))
+				typeSch = m
+			}
+			if kw == "type" {
+				// copy keywords of typeSch into result
+				for _, k := range typeSch.(*OrderedMap).Keys() {
+					tv, _ := typeSch.(*OrderedMap).Get(k)
+					result.Set(k, tv)
+				}
+			} else {
+				result.Set("not", typeSch)
+			}
+		case "dependencies":
+			if from.version < 4 && to.version >= 4 {
+				// draft3: single property dependency
+				if deps, ok := v.(*OrderedMap); ok {
+					arrDeps := NewOrderedMap()
+					for _, pname := range deps.Keys() {
+						pv, _ := deps.Get(pname)
+						if pv, ok := pv.(string); ok {
+							arrDeps.Set(pname, []interface{}{pv})
+						} else {
+							arrDeps.Set(pname, pv)
+						}
+					}
+					v = arrDeps
+				}
+			}
+			if to.version < 2019 {
+				if err := setProps(kw, v, kw); err != nil {
+					return nil, err
+				}
+				break
+			}
+			deps, ok := v.(*OrderedMap)
+			if !ok {
+				break
+			}
+			isArray := func(v interface{}) bool {
+				_, ok := v.([]interface{})
+				return ok
+			}
+			required := NewOrderedMap()
+			for _, pname := range deps.Keys() {
+				if pv, _ := deps.Get(pname); isArray(pv) {
+					required.Set(pname, pv)
+				}
+			}
+			if len(required.Keys()) > 0 {
+				result.Set("dependentRequired", required)
+			}
+			schemas, err := props(kw, v, "dependentSchemas", func(v interface{}) bool { return !isArray(v) })
+			if err != nil {
+				return nil, err
+			}
+			if len(schemas.Keys()) > 0 {
+				result.Set("dependentSchemas", schemas)
+			}
+		case "items":
+			if _, ok := v.([]interface{}); !ok {
+				item, err := sub(kw, v, kw)
+				if err != nil {
+					return nil, err
+				}
+				result.Set(kw, item)
+				break
+			}
+			if from.version < 2020 && to.version >= 2020 {
+				if err := subs(kw, v, "prefixItems"); err != nil {
+					return nil, err
+				}
+				if additional, ok := m.Get("additionalItems"); ok {
+					item, err := sub("additionalItems", additional, "items")
+					if err != nil {
+						return nil, err
+					}
+					result.Set("items", item)
+				}
+			} else if err := subs(kw, v, kw); err != nil {
+				return nil, err
+			}
+		case "additionalItems":
+			if from.version < 2020 && to.version >= 2020 {
+				// handled along with items. ignored without items array
+				break
+			}
+			item, err := sub(kw, v, kw)
+			if err != nil {
+				return nil, err
+			}
+			result.Set(kw, item)
+		case "allOf", "anyOf", "oneOf", "prefixItems":
+			if err := subs(kw, v, kw); err != nil {
+				return nil, err
+			}
+		case "not", "additionalProperties", "propertyNames", "contains", "if", "then", "else",
+			"unevaluatedProperties", "unevaluatedItems", "contentSchema":
+			item, err := sub(kw, v, kw)
+			if err != nil {
+				return nil, err
+			}
+			result.Set(kw, item)
+		case "$recursiveAnchor":
+			if v == true && loc == "#" {
+				mg.recursive[res] = true
+			}
+			if to.version >= 2020 {
+				if v == true {
+					result.Set("$dynamicAnchor", "meta")
+				}
+			} else {
+				result.Set(kw, v)
+			}
+		case "$recursiveRef":
+			if to.version >= 2020 {
+				if v == "#" {
+					v = "#meta"
+				}
+				result.Set("$dynamicRef", v)
+				mg.refs = append(mg.refs, migratedRef{result, "$dynamicRef", res})
+			} else {
+				result.Set(kw, v)
+				mg.refs = append(mg.refs, migratedRef{result, kw, res})
+			}
+		case "$ref", "$dynamicRef":
+			result.Set(kw, v)
+			mg.refs = append(mg.refs, migratedRef{result, kw, res})
+		default:
+			result.Set(kw, v)
+		}
+	}
+	return result, nil
+}
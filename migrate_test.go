@@ -0,0 +1,172 @@
+package jsonschema_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+func TestMigrate(t *testing.T) {
+	tests := []struct {
+		from, to  *jsonschema.Draft
+		schema    string
+		want      string
+		instances []string
+	}{
+		{
+			jsonschema.Draft4, jsonschema.Draft2020,
+			`{
+				"$schema": "http://json-schema.org/draft-04/schema#",
+				"id": "http://example.com/person.json",
+				"definitions": {
+					"age": {"type": "integer", "minimum": 0, "exclusiveMinimum": true, "maximum": 150, "exclusiveMaximum": false},
+					"name": {"id": "#name", "type": "string"}
+				},
+				"properties": {
+					"age": {"$ref": "#/definitions/age", "description": "ignored"},
+					"name": {"$ref": "#name"},
+					"tags": {"items": [{"type": "string"}, {"$ref": "#/definitions/age"}], "additionalItems": false},
+					"list": {"items": {"$ref": "#/properties/tags/items/0"}}
+				},
+				"dependencies": {"age": ["name"], "tags": {"required": ["list"]}}
+			}`,
+			`{
+				"$schema": "https://json-schema.org/draft/2020-12/schema",
+				"$id": "http://example.com/person.json",
+				"$defs": {
+					"age": {"type": "integer", "exclusiveMinimum": 0, "maximum": 150},
+					"name": {"$anchor": "name", "type": "string"}
+				},
+				"properties": {
+					"age": {"$ref": "#/$defs/age"},
+					"name": {"$ref": "#name"},
+					"tags": {"prefixItems": [{"type": "string"}, {"$ref": "#/$defs/age"}], "items": false},
+					"list": {"items": {"$ref": "#/properties/tags/prefixItems/0"}}
+				},
+				"dependentRequired": {"age": ["name"]},
+				"dependentSchemas": {"tags": {"required": ["list"]}}
+			}`,
+			[]string{
+				`{"age": 1, "name": "x"}`,
+				`{"age": 0, "name": "x"}`,
+				`{"age": 150, "name": "x"}`,
+				`{"age": 1}`,
+				`{"name": 1}`,
+				`{"tags": ["a", 1], "list": ["b"]}`,
+				`{"tags": ["a", 1, 2], "list": []}`,
+				`{"tags": ["a"]}`,
+				`{"list": [1]}`,
+			},
+		},
+		{
+			jsonschema.Draft3, jsonschema.Draft7,
+			`{
+				"type": ["string", {"type": "integer", "divisibleBy": 2}],
+				"extends": {"$ref": "#/type/1"},
+				"disallow": "integer",
+				"properties": {"a": {"type": "any", "required": true}},
+				"dependencies": {"a": "b"}
+			}`,
+			`{
+				"$schema": "http://json-schema.org/draft-07/schema",
+				"anyOf": [{"type": ["string"]}, {"type": "integer", "multipleOf": 2}],
+				"allOf": [{"$ref": "#/anyOf/1"}],
+				"not": {"type": "integer"},
+				"properties": {"a": {}},
+				"required": ["a"],
+				"dependencies": {"a": ["b"]}
+			}`,
+			[]string{`"x"`, `2`, `3`, `{"a": 1, "b": 1}`, `{"a": 1}`, `{}`},
+		},
+		{
+			jsonschema.Draft2019, jsonschema.Draft2020,
+			`{
+				"$recursiveAnchor": true,
+				"type": "object",
+				"properties": {
+					"children": {"type": "array", "items": {"$recursiveRef": "#"}},
+					"other": {"$id": "other.json", "items": [{"$recursiveRef": "#"}]}
+				}
+			}`,
+			`{
+				"$schema": "https://json-schema.org/draft/2020-12/schema",
+				"$dynamicAnchor": "meta",
+				"type": "object",
+				"properties": {
+					"children": {"type": "array", "items": {"$dynamicRef": "#meta"}},
+					"other": {"$id": "other.json", "prefixItems": [{"$ref": "#"}]}
+				}
+			}`,
+			[]string{
+				`{"children": [{}]}`,
+				`{"children": [1]}`,
+				`{"other": [[[]]]}`,
+				`{"other": [[1]]}`,
+			},
+		},
+		{
+			jsonschema.Draft6, jsonschema.Draft7,
+			`{"$id": "#root", "definitions": {"a": {"exclusiveMinimum": 1}}, "$ref": "#/definitions/a"}`,
+			`{"$schema": "http://json-schema.org/draft-07/schema", "$id": "#root", "definitions": {"a": {"exclusiveMinimum": 1}}, "$ref": "#/definitions/a"}`,
+			[]string{`1`, `2`},
+		},
+	}
+	for i, test := range tests {
+		c := jsonschema.NewCompiler()
+		c.Draft = test.from
+		if err := c.AddResource("http://example.com/person.json", strings.NewReader(test.schema)); err != nil {
+			t.Fatal(err)
+		}
+		sch, err := c.Compile("http://example.com/person.json")
+		if err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+
+		got, err := jsonschema.Migrate(decodeSchema(t, test.schema), test.from, test.to)
+		if err != nil {
+			t.Fatalf("#%d: %#v", i, err)
+		}
+		gotJSON, _ := json.Marshal(got)
+		wantJSON, _ := json.Marshal(decodeSchema(t, test.want))
+		if !bytes.Equal(gotJSON, wantJSON) {
+			t.Errorf("#%d:\n got %s\nwant %s", i, gotJSON, wantJSON)
+			continue
+		}
+
+		mc := jsonschema.NewCompiler()
+		if err := mc.AddResource("http://example.com/person.json", bytes.NewReader(gotJSON)); err != nil {
+			t.Fatal(err)
+		}
+		msch, err := mc.Compile("http://example.com/person.json")
+		if err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+		for _, instance := range test.instances {
+			want := sch.Validate(decodeString(t, instance)) == nil
+			got := msch.Validate(decodeString(t, instance)) == nil
+			if got != want {
+				t.Errorf("#%d: %s: got %v, want %v", i, instance, got, want)
+			}
+		}
+	}
+}
+
+func TestMigrate_Downgrade(t *testing.T) {
+	_, err := jsonschema.Migrate(decodeSchema(t, `{"type": "string"}`), jsonschema.Draft2020, jsonschema.Draft7)
+	if err == nil {
+		t.Fatal("error expected")
+	}
+}
+
+func decodeSchema(t *testing.T, s string) interface{} {
+	t.Helper()
+	m := jsonschema.NewOrderedMap()
+	m.SetUseNumber(true)
+	if err := json.Unmarshal([]byte(s), m); err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
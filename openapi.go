@@ -0,0 +1,310 @@
+package jsonschema
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// AddOpenAPI adds OpenAPI 3.0 or 3.1 document read from r, at given url.
+//
+// The schemas in the document, i.e. those in components and those inline in
+// path items, are indexed so that they can be compiled using json-pointer.
+// For example "api.json#/components/schemas/Pet".
+//
+// Schemas in OpenAPI 3.0 documents use OpenAPI30 draft. Schemas in OpenAPI 3.1
+// documents use the dialect specified by "jsonSchemaDialect", which defaults to
+// OpenAPI31.
+//
+// Note that OpenAPI documents loaded while resolving "$ref" are also detected,
+// so "$ref" across multiple OpenAPI documents work.
+func (c *Compiler) AddOpenAPI(url string, r io.Reader) error {
+	res, err := newResource(url, r)
+	if err != nil {
+		return err
+	}
+	if !isOpenAPI(res.doc) {
+		return fmt.Errorf("jsonschema: %s is not an OpenAPI document", url)
+	}
+	c.resources[res.url] = res
+	_, err = c.findResource(res.url)
+	return err
+}
+
+// isOpenAPI tells whether doc is OpenAPI 3.x document.
+func isOpenAPI(doc interface{}) bool {
+	m, ok := doc.(*OrderedMap)
+	if !ok {
+		return false
+	}
+	if _, ok := m.Get("$schema"); ok {
+		return false
+	}
+	v, ok := m.Get("openapi")
+	if !ok {
+		return false
+	}
+	version, ok := v.(string)
+	return ok && strings.HasPrefix(version, "3.")
+}
+
+// loadOpenAPI sets the draft of OpenAPI document r and indexes
+// its schemas into r.subresources.
+func (c *Compiler) loadOpenAPI(r *resource) error {
+	m := r.doc.(*OrderedMap)
+	version, _ := m.Get("openapi")
+	switch {
+	case strings.HasPrefix(version.(string), "3.0."):
+		r.draft = OpenAPI30
+	case strings.HasPrefix(version.(string), "3.1."):
+		r.draft = OpenAPI31
+		if dialect, ok := m.Get("jsonSchemaDialect"); ok {
+			if _, ok := dialect.(string); !ok {
+				return fmt.Errorf("jsonschema: invalid jsonSchemaDialect in %s", r.url)
+			}
+			d, err := c.findDialect(dialect.(string))
+			if err != nil {
+				return fmt.Errorf("jsonschema: invalid jsonSchemaDialect in %s: %v", r.url, err)
+			}
+			r.draft = d
+		}
+	default:
+		return fmt.Errorf("jsonschema: unsupported OpenAPI version %s in %s", version, r.url)
+	}
+
+	var schemas []*resource
+	walkOpenAPI(m, func(loc string, sch interface{}) {
+		schemas = append(schemas, &resource{floc: "#" + loc, doc: sch})
+	})
+	r.subresources = make(map[string]*resource)
+	for _, sr := range schemas {
+		url, err := r.draft.resolveID(r.url, sr.doc)
+		if err != nil {
+			return err
+		}
+		sr.url = url
+		r.subresources[sr.floc] = sr
+		if err := r.fillSubschemas(c, sr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// walkOpenAPI calls f with json-pointer and value of each Schema Object
+// in OpenAPI document doc.
+func walkOpenAPI(doc *OrderedMap, f func(loc string, sch interface{})) {
+	// object returns v as map, if it is an object other than Reference Object.
+	object := func(v interface{}) (*OrderedMap, bool) {
+		m, ok := v.(*OrderedMap)
+		if !ok {
+			return nil, false
+		}
+		if _, ok := m.Get("$ref"); ok {
+			return nil, false
+		}
+		return m, true
+	}
+	field := func(m *OrderedMap, loc, name string, walk func(loc string, v interface{})) {
+		if v, ok := m.Get(name); ok {
+			walk(loc+"/"+escape(name), v)
+		}
+	}
+	each := func(walk func(loc string, v interface{})) func(loc string, v interface{}) {
+		return func(loc string, v interface{}) {
+			switch v := v.(type) {
+			case *OrderedMap:
+				for _, k := range v.Keys() {
+					item, _ := v.Get(k)
+					walk(loc+"/"+escape(k), item)
+				}
+			case []interface{}:
+				for i, item := range v {
+					walk(loc+"/"+strconv.Itoa(i), item)
+				}
+			}
+		}
+	}
+
+	content := func(loc string, v interface{}) {
+		if m, ok := object(v); ok {
+			field(m, loc, "schema", f)
+		}
+	}
+	header := func(loc string, v interface{}) {
+		if m, ok := object(v); ok {
+			field(m, loc, "schema", f)
+			field(m, loc, "content", each(content))
+		}
+	}
+	parameter := header
+	requestBody := func(loc string, v interface{}) {
+		if m, ok := object(v); ok {
+			field(m, loc, "content", each(content))
+		}
+	}
+	response := func(loc string, v interface{}) {
+		if m, ok := object(v); ok {
+			field(m, loc, "headers", each(header))
+			field(m, loc, "content", each(content))
+		}
+	}
+	var pathItem func(loc string, v interface{})
+	callback := func(loc string, v interface{}) {
+		if _, ok := object(v); ok {
+			each(pathItem)(loc, v)
+		}
+	}
+	operation := func(loc string, v interface{}) {
+		if m, ok := object(v); ok {
+			field(m, loc, "parameters", each(parameter))
+			field(m, loc, "requestBody", requestBody)
+			field(m, loc, "responses", each(response))
+			field(m, loc, "callbacks", each(callback))
+		}
+	}
+	pathItem = func(loc string, v interface{}) {
+		if m, ok := object(v); ok {
+			field(m, loc, "parameters", each(parameter))
+			for _, method := range []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"} {
+				field(m, loc, method, operation)
+			}
+		}
+	}
+
+	field(doc, "", "paths", each(pathItem))
+	field(doc, "", "webhooks", each(pathItem))
+	if components, ok := doc.Get("components"); ok {
+		if components, ok := components.(*OrderedMap); ok {
+			loc := "/components"
+			field(components, loc, "schemas", each(f))
+			field(components, loc, "parameters", each(parameter))
+			field(components, loc, "headers", each(header))
+			field(components, loc, "requestBodies", each(requestBody))
+			field(components, loc, "responses", each(response))
+			field(components, loc, "callbacks", each(callback))
+			field(components, loc, "pathItems", each(pathItem))
+		}
+	}
+}
+
+// compileDiscriminator compiles "discriminator" v in res into res.schema.
+//
+// The alternatives of oneOf, or anyOf, are indexed by discriminator values
+// specified in mapping, by schema names in OpenAPI documents, and by the
+// const/enum values of discriminator property in alternatives.
+func (c *Compiler) compileDiscriminator(r *resource, stack []schemaRef, res *resource, v interface{}) error {
+	invalid := fmt.Errorf("jsonschema: invalid discriminator in %s", res)
+	m, ok := v.(*OrderedMap)
+	if !ok {
+		return invalid
+	}
+	pname, ok := m.Get("propertyName")
+	if _, isString := pname.(string); !ok || !isString {
+		return invalid
+	}
+	s := res.schema
+	d := &Discriminator{PropertyName: pname.(string)}
+	if mapping, ok := m.Get("mapping"); ok {
+		mapping, ok := mapping.(*OrderedMap)
+		if !ok {
+			return invalid
+		}
+		d.Mapping = make(map[string]string, len(mapping.Keys()))
+		for _, value := range mapping.Keys() {
+			ref, _ := mapping.Get(value)
+			if _, ok := ref.(string); !ok {
+				return invalid
+			}
+			d.Mapping[value] = ref.(string)
+		}
+	}
+	s.Discriminator = d
+
+	alternatives, keyword := s.OneOf, "oneOf"
+	if len(alternatives) == 0 {
+		alternatives, keyword = s.AnyOf, "anyOf"
+	}
+	if len(alternatives) == 0 {
+		return nil
+	}
+	index := func(sch *Schema) int {
+		for i, alt := range alternatives {
+			if alt == sch || alt.Ref == sch {
+				return i
+			}
+		}
+		return -1
+	}
+
+	d.keyword, d.alternatives = keyword, make(map[string]int)
+	if mapping, ok := m.Get("mapping"); ok {
+		for _, value := range mapping.(*OrderedMap).Keys() {
+			ref := d.Mapping[value]
+			if r.draft.openapi && !strings.ContainsAny(ref, "/#") {
+				// schema name
+				ref = r.url + "#/components/schemas/" + escape(ref)
+			}
+			sch, err := c.compileRef(r, stack, "discriminator/mapping/"+escape(value), res, ref)
+			if err != nil {
+				return err
+			}
+			if i := index(sch); i != -1 {
+				d.alternatives[value] = i
+			}
+		}
+	}
+	for i, alt := range alternatives {
+		var values []string
+		if r.draft.openapi && alt.Ref != nil {
+			const prefix = "#/components/schemas/"
+			if j := strings.Index(alt.Ref.Location, prefix); j != -1 {
+				if name := alt.Ref.Location[j+len(prefix):]; !strings.Contains(name, "/") {
+					values = append(values, unescape(name))
+				}
+			}
+		}
+		values = append(values, discriminatorValues(alt, d.PropertyName)...)
+		for _, value := range values {
+			if _, ok := d.alternatives[value]; !ok {
+				d.alternatives[value] = i
+			}
+		}
+	}
+	if len(d.alternatives) == 0 {
+		d.keyword, d.alternatives = "", nil
+	}
+	return nil
+}
+
+// discriminatorValues returns the values of property pname, that
+// are specified by const/enum in sch.
+func discriminatorValues(sch *Schema, pname string) []string {
+	var values []string
+	seen := make(map[*Schema]bool)
+	var collect func(sch *Schema)
+	collect = func(sch *Schema) {
+		for ; sch != nil && !seen[sch]; sch = sch.Ref {
+			seen[sch] = true
+			if sch.Properties != nil {
+				if p, ok := sch.Properties.Get(pname); ok {
+					p := p.(*Schema)
+					for p.Ref != nil && len(p.Constant) == 0 && len(p.Enum) == 0 {
+						p = p.Ref
+					}
+					for _, v := range append(p.Constant, p.Enum...) {
+						if v, ok := v.(string); ok {
+							values = append(values, v)
+						}
+					}
+				}
+			}
+			for _, sch := range sch.AllOf {
+				collect(sch)
+			}
+		}
+	}
+	collect(sch)
+	return values
+}
@@ -0,0 +1,309 @@
+package jsonschema_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+func TestOpenAPI30_InvalidSchemas(t *testing.T) {
+	tests := []struct {
+		description string
+		schema      string
+	}{
+		{"type must be string", `{"type": ["string", "null"]}`},
+		{"null type is not allowed", `{"type": "null"}`},
+		{"nullable must be boolean", `{"nullable": "true"}`},
+		{"discriminator requires propertyName", `{"discriminator": {}}`},
+		{"discriminator mapping values must be string", `{"discriminator": {"propertyName": "kind", "mapping": {"a": 1}}}`},
+		{"unknown keyword", `{"patternProperties": {}}`},
+		{"exclusiveMinimum must be boolean", `{"exclusiveMinimum": 5}`},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			c := jsonschema.NewCompiler()
+			c.Draft = jsonschema.OpenAPI30
+			if err := c.AddResource("schema.json", strings.NewReader(test.schema)); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := c.Compile("schema.json"); err == nil {
+				t.Error("error expected")
+			} else {
+				t.Log(err)
+			}
+		})
+	}
+}
+
+func TestOpenAPI30_ExtractAnnotations(t *testing.T) {
+	c := jsonschema.NewCompiler()
+	c.Draft = jsonschema.OpenAPI30
+	c.ExtractAnnotations = true
+	if err := c.AddResource("schema.json", strings.NewReader(`{
+		"type": "string",
+		"example": "foo",
+		"readOnly": true,
+		"deprecated": true
+	}`)); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.Compile("schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sch.Examples) != 1 || sch.Examples[0] != "foo" {
+		t.Errorf("examples: got %v", sch.Examples)
+	}
+	if !sch.ReadOnly {
+		t.Error("readOnly: got false")
+	}
+	if !sch.Deprecated {
+		t.Error("deprecated: got false")
+	}
+}
+
+func TestAddOpenAPI(t *testing.T) {
+	api := `{
+		"openapi": "3.1.0",
+		"info": {"title": "pets", "version": "1.0"},
+		"paths": {
+			"/pets/{id}": {
+				"parameters": [
+					{"name": "id", "in": "path", "required": true, "schema": {"type": "integer", "minimum": 1}}
+				],
+				"put": {
+					"requestBody": {
+						"content": {
+							"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}
+						}
+					},
+					"responses": {
+						"200": {
+							"headers": {"X-Rate-Limit": {"schema": {"type": "integer"}}},
+							"content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/Pet"}}}}
+						},
+						"default": {"$ref": "common.json#/components/responses/Error"}
+					}
+				}
+			}
+		},
+		"components": {
+			"schemas": {
+				"Pet": {
+					"type": "object",
+					"properties": {
+						"name": {"type": "string"},
+						"tag": {"$ref": "common.json#/components/schemas/Tag"}
+					},
+					"required": ["name"],
+					"example": {"name": "tom"}
+				}
+			}
+		}
+	}`
+	common := `{
+		"openapi": "3.0.3",
+		"info": {"title": "common", "version": "1.0"},
+		"paths": {},
+		"components": {
+			"schemas": {
+				"Tag": {"type": "string", "nullable": true}
+			},
+			"responses": {
+				"Error": {
+					"description": "error",
+					"content": {"application/json": {"schema": {"type": "object", "required": ["code"]}}}
+				}
+			}
+		}
+	}`
+	tests := []struct {
+		ptr     string
+		valid   []string
+		invalid []string
+	}{
+		{"/components/schemas/Pet", []string{`{"name": "tom"}`, `{"name": "tom", "tag": null}`}, []string{`{}`, `{"name": "tom", "tag": 1}`}},
+		{"/paths/~1pets~1{id}/parameters/0/schema", []string{`1`}, []string{`0`}},
+		{"/paths/~1pets~1{id}/put/requestBody/content/application~1json/schema", []string{`{"name": "tom"}`}, []string{`{}`}},
+		{"/paths/~1pets~1{id}/put/responses/200/headers/X-Rate-Limit/schema", []string{`1`}, []string{`"1"`}},
+		{"/paths/~1pets~1{id}/put/responses/200/content/application~1json/schema", []string{`[]`, `[{"name": "tom"}]`}, []string{`[{}]`}},
+	}
+	for _, test := range tests {
+		t.Run(test.ptr, func(t *testing.T) {
+			c := jsonschema.NewCompiler()
+			if err := c.AddOpenAPI("api.json", strings.NewReader(api)); err != nil {
+				t.Fatal(err)
+			}
+			if err := c.AddResource("common.json", strings.NewReader(common)); err != nil {
+				t.Fatal(err)
+			}
+			sch, err := c.Compile("api.json#" + test.ptr)
+			if err != nil {
+				t.Fatalf("%#v", err)
+			}
+			for _, v := range test.valid {
+				if err := sch.Validate(decodeString(t, v)); err != nil {
+					t.Errorf("%s: %v", v, err)
+				}
+			}
+			for _, v := range test.invalid {
+				if err := sch.Validate(decodeString(t, v)); err == nil {
+					t.Errorf("%s: validation must fail", v)
+				}
+			}
+		})
+	}
+
+	t.Run("referenced response", func(t *testing.T) {
+		c := jsonschema.NewCompiler()
+		if err := c.AddResource("common.json", strings.NewReader(common)); err != nil {
+			t.Fatal(err)
+		}
+		sch, err := c.Compile("common.json#/components/responses/Error/content/application~1json/schema")
+		if err != nil {
+			t.Fatalf("%#v", err)
+		}
+		if err := sch.Validate(decodeString(t, `{}`)); err == nil {
+			t.Error("validation must fail")
+		}
+	})
+}
+
+func TestAddOpenAPI_Dialect(t *testing.T) {
+	c := jsonschema.NewCompiler()
+	if err := c.AddOpenAPI("api.json", strings.NewReader(`{
+		"openapi": "3.1.0",
+		"jsonSchemaDialect": "http://json-schema.org/draft-07/schema#",
+		"components": {
+			"schemas": {
+				"Pair": {"items": [{"type": "string"}, {"type": "integer"}], "additionalItems": false}
+			}
+		}
+	}`)); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.Compile("api.json#/components/schemas/Pair")
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+	if err := sch.Validate(decodeString(t, `["a", 1]`)); err != nil {
+		t.Error(err)
+	}
+	if err := sch.Validate(decodeString(t, `["a", 1, 2]`)); err == nil {
+		t.Error("validation must fail")
+	}
+}
+
+func TestAddOpenAPI_Invalid(t *testing.T) {
+	tests := []struct {
+		description string
+		doc         string
+	}{
+		{"not openapi document", `{"type": "string"}`},
+		{"unsupported version", `{"openapi": "3.2.0"}`},
+		{"invalid jsonSchemaDialect", `{"openapi": "3.1.0", "jsonSchemaDialect": 1}`},
+		{"invalid schema", `{"openapi": "3.1.0", "components": {"schemas": {"Pet": {"type": 1}}}}`},
+		{"invalid openapi30 schema", `{"openapi": "3.0.0", "components": {"schemas": {"Pet": {"type": "null"}}}}`},
+		{"invalid discriminator", `{"openapi": "3.1.0", "components": {"schemas": {"Pet": {"properties": {"a": {"discriminator": {}}}}}}}`},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			c := jsonschema.NewCompiler()
+			if err := c.AddOpenAPI("api.json", strings.NewReader(test.doc)); err == nil {
+				t.Error("error expected")
+			} else {
+				t.Log(err)
+			}
+		})
+	}
+}
+
+func TestDiscriminator(t *testing.T) {
+	schema := `{
+		"$defs": {
+			"cat": {"properties": {"kind": {"const": "cat"}, "lives": {"type": "integer"}}},
+			"dog": {"properties": {"kind": {"const": "dog"}, "bark": {"type": "boolean"}}}
+		},
+		"oneOf": [{"$ref": "#/$defs/cat"}, {"$ref": "#/$defs/dog"}],
+		"discriminator": {"propertyName": "kind"}
+	}`
+	tests := []struct {
+		doc     string
+		keyword string // keyword location of error, when discriminator is enabled
+	}{
+		{`{"kind": "cat", "lives": 9}`, ""},
+		{`{"kind": "cat", "lives": "9"}`, "/oneOf/0/$ref/properties/lives/type"},
+		{`{"kind": "fox"}`, "/discriminator"},
+		{`{"lives": 9}`, "/discriminator"},
+	}
+	for _, enabled := range []bool{false, true} {
+		c := jsonschema.NewCompiler()
+		c.Discriminator = enabled
+		if err := c.AddResource("schema.json", strings.NewReader(schema)); err != nil {
+			t.Fatal(err)
+		}
+		sch, err := c.Compile("schema.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, test := range tests {
+			err := sch.Validate(decodeString(t, test.doc))
+			if (err == nil) != (test.keyword == "") {
+				t.Errorf("enabled=%v %s: got %v", enabled, test.doc, err)
+				continue
+			}
+			if err == nil || !enabled {
+				continue
+			}
+			verr := err.(*jsonschema.ValidationError)
+			t.Logf("%#v", verr)
+			if len(verr.Causes) != 1 {
+				t.Errorf("%s: got %d causes, want 1", test.doc, len(verr.Causes))
+				continue
+			}
+			leaf := verr.Causes[0]
+			for len(leaf.Causes) > 0 {
+				leaf = leaf.Causes[0]
+			}
+			if leaf.KeywordLocation != test.keyword {
+				t.Errorf("%s: got %s, want %s", test.doc, leaf.KeywordLocation, test.keyword)
+			}
+		}
+	}
+}
+
+func TestAddOpenAPI_Discriminator(t *testing.T) {
+	c := jsonschema.NewCompiler()
+	if err := c.AddOpenAPI("api.json", strings.NewReader(`{
+		"openapi": "3.0.3",
+		"components": {
+			"schemas": {
+				"Pet": {
+					"oneOf": [{"$ref": "#/components/schemas/Cat"}, {"$ref": "#/components/schemas/Dog"}],
+					"discriminator": {"propertyName": "petType", "mapping": {"puppy": "Dog"}}
+				},
+				"Cat": {"type": "object", "properties": {"lives": {"type": "integer"}}},
+				"Dog": {"type": "object", "properties": {"bark": {"type": "boolean"}}}
+			}
+		}
+	}`)); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := c.Compile("api.json#/components/schemas/Pet")
+	if err != nil {
+		t.Fatalf("%#v", err)
+	}
+	valid := []string{`{"petType": "Cat", "lives": 9}`, `{"petType": "Dog", "bark": true}`, `{"petType": "puppy", "bark": true}`}
+	invalid := []string{`{"petType": "Cat", "lives": "9"}`, `{"petType": "puppy", "bark": 1}`, `{"petType": "Fox"}`}
+	for _, v := range valid {
+		if err := sch.Validate(decodeString(t, v)); err != nil {
+			t.Errorf("%s: %v", v, err)
+		}
+	}
+	for _, v := range invalid {
+		if err := sch.Validate(decodeString(t, v)); err == nil {
+			t.Errorf("%s: validation must fail", v)
+		}
+	}
+}
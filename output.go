@@ -20,10 +20,12 @@ type Basic struct {
 
 // BasicError is output unit in basic format.
 type BasicError struct {
-	KeywordLocation         string `json:"keywordLocation"`
-	AbsoluteKeywordLocation string `json:"absoluteKeywordLocation"`
-	InstanceLocation        string `json:"instanceLocation"`
-	Error                   string `json:"error"`
+	KeywordLocation         string    `json:"keywordLocation"`
+	AbsoluteKeywordLocation string    `json:"absoluteKeywordLocation"`
+	InstanceLocation        string    `json:"instanceLocation"`
+	Error                   string    `json:"error"`
+	Keyword                 string    `json:"keyword,omitempty"`
+	Details                 ErrorKind `json:"details,omitempty"`
 }
 
 // BasicOutput returns output in basic format
@@ -36,6 +38,8 @@ func (ve *ValidationError) BasicOutput() Basic {
 			AbsoluteKeywordLocation: ve.AbsoluteKeywordLocation,
 			InstanceLocation:        ve.InstanceLocation,
 			Error:                   ve.Message,
+			Keyword:                 ve.Keyword,
+			Details:                 ve.Kind,
 		})
 		for _, cause := range ve.Causes {
 			flatten(cause)
@@ -54,6 +58,8 @@ type Detailed struct {
 	AbsoluteKeywordLocation string     `json:"absoluteKeywordLocation"`
 	InstanceLocation        string     `json:"instanceLocation"`
 	Error                   string     `json:"error,omitempty"`
+	Keyword                 string     `json:"keyword,omitempty"`
+	Details                 ErrorKind  `json:"details,omitempty"`
 	Errors                  []Detailed `json:"errors,omitempty"`
 }
 
@@ -72,6 +78,8 @@ func (ve *ValidationError) DetailedOutput() Detailed {
 		AbsoluteKeywordLocation: ve.AbsoluteKeywordLocation,
 		InstanceLocation:        ve.InstanceLocation,
 		Error:                   message,
+		Keyword:                 ve.Keyword,
+		Details:                 ve.Kind,
 		Errors:                  errors,
 	}
 }
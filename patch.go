@@ -0,0 +1,469 @@
+package jsonschema
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PatchError is the error type returned by ApplyPatch and ValidatePatch.
+type PatchError struct {
+	Index int   // index of patch operation responsible. -1 if not known.
+	Err   error // *ValidationError if patched document is invalid, otherwise why operation failed.
+}
+
+func (pe *PatchError) Error() string {
+	msg := strings.TrimPrefix(pe.Err.Error(), "jsonschema: ")
+	if pe.Index == -1 {
+		return "jsonschema: " + msg
+	}
+	return fmt.Sprintf("jsonschema: patch operation %d: %s", pe.Index, msg)
+}
+
+func (pe *PatchError) Unwrap() error {
+	return pe.Err
+}
+
+// patchOp is parsed json patch operation.
+type patchOp struct {
+	op       string
+	path     []string
+	from     []string
+	value    interface{}
+	hasValue bool
+}
+
+func parsePatchOp(v interface{}) (*patchOp, error) {
+	if om, ok := v.(*OrderedMap); ok {
+		v = om.RawValues()
+	}
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("operation must be object")
+	}
+	op := &patchOp{}
+	if op.op, ok = m["op"].(string); !ok {
+		return nil, errors.New(`"op" must be string`)
+	}
+	ptr := func(name string) ([]string, error) {
+		s, ok := m[name].(string)
+		if !ok {
+			return nil, fmt.Errorf("%s must be string", quote(name))
+		}
+		return parsePtr(s)
+	}
+	var err error
+	if op.path, err = ptr("path"); err != nil {
+		return nil, err
+	}
+	switch op.op {
+	case "add", "replace", "test":
+		if op.value, op.hasValue = m["value"]; !op.hasValue {
+			return nil, errors.New(`"value" is missing`)
+		}
+	case "move", "copy":
+		if op.from, err = ptr("from"); err != nil {
+			return nil, err
+		}
+	case "remove":
+	default:
+		return nil, fmt.Errorf("unknown op %s", quote(op.op))
+	}
+	return op, nil
+}
+
+// affected returns the locations whose subtrees are affected by op.
+func (op *patchOp) affected() [][]string {
+	parent := func(tokens []string) []string {
+		if len(tokens) == 0 {
+			return tokens
+		}
+		return tokens[:len(tokens)-1]
+	}
+	switch op.op {
+	case "test":
+		return nil
+	case "move":
+		return [][]string{parent(op.from), parent(op.path)}
+	}
+	return [][]string{parent(op.path)}
+}
+
+// ApplyPatch applies RFC 6902 JSON Patch to doc, and returns the result.
+// doc is not modified. patch must be the decoded json patch document, which
+// is array of operations.
+//
+// Returns *PatchError if an operation cannot be applied.
+func ApplyPatch(doc, patch interface{}) (interface{}, error) {
+	doc, _, err := applyPatch(doc, patch)
+	return doc, err
+}
+
+func applyPatch(doc, patch interface{}) (interface{}, []*patchOp, error) {
+	arr, ok := patch.([]interface{})
+	if !ok {
+		return nil, nil, &PatchError{-1, errors.New("json patch must be array")}
+	}
+	doc = deepCopy(doc)
+	ops := make([]*patchOp, len(arr))
+	for i, item := range arr {
+		op, err := parsePatchOp(item)
+		if err == nil {
+			doc, err = op.apply(doc)
+		}
+		if err != nil {
+			return nil, nil, &PatchError{i, err}
+		}
+		ops[i] = op
+	}
+	return doc, ops, nil
+}
+
+func (op *patchOp) apply(doc interface{}) (interface{}, error) {
+	switch op.op {
+	case "add":
+		return patchAdd(doc, op.path, deepCopy(op.value))
+	case "remove":
+		doc, _, err := patchRemove(doc, op.path)
+		return doc, err
+	case "replace":
+		if _, err := patchGet(doc, op.path); err != nil {
+			return nil, err
+		}
+		if len(op.path) == 0 {
+			return deepCopy(op.value), nil
+		}
+		return patchUpdate(doc, op.path, func(parent interface{}, tok string) (interface{}, error) {
+			return setChild(parent, tok, deepCopy(op.value)), nil
+		})
+	case "move":
+		if hasPrefix(op.path, op.from) && len(op.path) > len(op.from) {
+			return nil, fmt.Errorf("cannot move %s into its child", quote(instanceLocation(op.from)))
+		}
+		doc, v, err := patchRemove(doc, op.from)
+		if err != nil {
+			return nil, err
+		}
+		return patchAdd(doc, op.path, v)
+	case "copy":
+		v, err := patchGet(doc, op.from)
+		if err != nil {
+			return nil, err
+		}
+		return patchAdd(doc, op.path, deepCopy(v))
+	default: // test
+		v, err := patchGet(doc, op.path)
+		if err != nil {
+			return nil, err
+		}
+		if !equals(v, op.value) {
+			return nil, fmt.Errorf("test failed at %s", quote(instanceLocation(op.path)))
+		}
+		return doc, nil
+	}
+}
+
+func patchGet(doc interface{}, tokens []string) (interface{}, error) {
+	for i, tok := range tokens {
+		v, ok := child(doc, tok)
+		if !ok {
+			return nil, fmt.Errorf("%s not found", quote(instanceLocation(tokens[:i+1])))
+		}
+		doc = v
+	}
+	return doc, nil
+}
+
+// patchUpdate calls f with the parent of value at tokens, and replaces the
+// parent with the value returned by f. tokens must not be empty.
+func patchUpdate(doc interface{}, tokens []string, f func(parent interface{}, tok string) (interface{}, error)) (interface{}, error) {
+	if _, err := patchGet(doc, tokens[:len(tokens)-1]); err != nil {
+		return nil, err
+	}
+	var update func(v interface{}, tokens []string) (interface{}, error)
+	update = func(v interface{}, tokens []string) (interface{}, error) {
+		if len(tokens) == 1 {
+			return f(v, tokens[0])
+		}
+		cv, _ := child(v, tokens[0])
+		cv, err := update(cv, tokens[1:])
+		if err != nil {
+			return nil, err
+		}
+		return setChild(v, tokens[0], cv), nil
+	}
+	return update(doc, tokens)
+}
+
+func patchAdd(doc interface{}, tokens []string, value interface{}) (interface{}, error) {
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	return patchUpdate(doc, tokens, func(parent interface{}, tok string) (interface{}, error) {
+		switch p := parent.(type) {
+		case map[string]interface{}, *OrderedMap:
+			return setChild(p, tok, value), nil
+		case []interface{}:
+			i := len(p)
+			if tok != "-" {
+				var err error
+				if i, err = strconv.Atoi(tok); err != nil || i < 0 || i > len(p) || strconv.Itoa(i) != tok {
+					return nil, fmt.Errorf("invalid array index at %s", quote(instanceLocation(tokens)))
+				}
+			}
+			p = append(p, nil)
+			copy(p[i+1:], p[i:])
+			p[i] = value
+			return p, nil
+		}
+		return nil, fmt.Errorf("cannot add %s to %s", quote(instanceLocation(tokens)), jsonType(parent))
+	})
+}
+
+// patchRemove removes the value at tokens, and returns the removed value.
+func patchRemove(doc interface{}, tokens []string) (interface{}, interface{}, error) {
+	if len(tokens) == 0 {
+		return nil, nil, errors.New("cannot remove root")
+	}
+	var removed interface{}
+	doc, err := patchUpdate(doc, tokens, func(parent interface{}, tok string) (interface{}, error) {
+		v, ok := child(parent, tok)
+		if !ok {
+			return nil, fmt.Errorf("%s not found", quote(instanceLocation(tokens)))
+		}
+		removed = v
+		switch p := parent.(type) {
+		case map[string]interface{}:
+			delete(p, tok)
+		case *OrderedMap:
+			p.Delete(tok)
+		case []interface{}:
+			i, _ := strconv.Atoi(tok)
+			return append(p[:i], p[i+1:]...), nil
+		}
+		return parent, nil
+	})
+	return doc, removed, err
+}
+
+// setChild sets property or item tok of v, and returns v.
+func setChild(v interface{}, tok string, value interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		v[tok] = value
+	case *OrderedMap:
+		v.Set(tok, value)
+	case []interface{}:
+		i, _ := strconv.Atoi(tok)
+		v[i] = value
+	}
+	return v
+}
+
+func deepCopy(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			m[k] = deepCopy(item)
+		}
+		return m
+	case *OrderedMap:
+		m := NewOrderedMap()
+		for _, k := range v.Keys() {
+			item, _ := v.Get(k)
+			m.Set(k, deepCopy(item))
+		}
+		return m
+	case []interface{}:
+		arr := make([]interface{}, len(v))
+		for i, item := range v {
+			arr[i] = deepCopy(item)
+		}
+		return arr
+	}
+	return v
+}
+
+func hasPrefix(tokens, prefix []string) bool {
+	if len(prefix) > len(tokens) {
+		return false
+	}
+	for i := range prefix {
+		if tokens[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// MergePatch applies RFC 7396 JSON Merge Patch to doc, and returns the result.
+// doc is not modified.
+func MergePatch(doc, patch interface{}) interface{} {
+	return mergePatch(deepCopy(doc), patch, nil, nil)
+}
+
+// mergePatch merges patch into doc at location tokens, appending the locations
+// whose subtrees are affected to affected.
+func mergePatch(doc, patch interface{}, tokens []string, affected *[][]string) interface{} {
+	changed := func() {
+		if affected != nil && len(tokens) > 0 {
+			*affected = append(*affected, tokens[:len(tokens)-1])
+		} else if affected != nil {
+			*affected = append(*affected, tokens)
+		}
+	}
+	var keys []string
+	var p map[string]interface{}
+	switch patch := patch.(type) {
+	case *OrderedMap:
+		keys, p = patch.Keys(), patch.RawValues()
+	case map[string]interface{}:
+		keys, p = sortedKeys(patch), patch
+	default:
+		changed()
+		return deepCopy(patch)
+	}
+	switch doc.(type) {
+	case map[string]interface{}, *OrderedMap:
+	default:
+		changed()
+		doc = map[string]interface{}{}
+	}
+	for _, k := range keys {
+		if p[k] == nil {
+			if _, ok := child(doc, k); ok {
+				if affected != nil {
+					*affected = append(*affected, tokens)
+				}
+				if om, ok := doc.(*OrderedMap); ok {
+					om.Delete(k)
+				} else {
+					delete(doc.(map[string]interface{}), k)
+				}
+			}
+			continue
+		}
+		v, _ := child(doc, k)
+		ktokens := append(tokens[:len(tokens):len(tokens)], k)
+		doc = setChild(doc, k, mergePatch(v, p[k], ktokens, affected))
+	}
+	return doc
+}
+
+// ValidatePatch applies RFC 6902 JSON Patch to doc, and validates the result
+// against s. doc is not modified. It returns the patched document.
+//
+// If affectedOnly is true, only the subtrees affected by the patch are
+// validated, i.e. the parents of locations added, removed or replaced. In
+// this mode, subschemas are found only through "properties", "patternProperties",
+// "additionalProperties", "items", "prefixItems", "additionalItems", "$ref"
+// and "allOf", and keywords of ancestors such as "unevaluatedProperties" are
+// not checked.
+//
+// Returns *PatchError, if patch cannot be applied, or if patched document
+// is invalid. In latter case, Err is *ValidationError and Index is the last
+// operation that affected the location of ValidationError.BestMatch.
+func (s *Schema) ValidatePatch(doc, patch interface{}, affectedOnly bool) (interface{}, error) {
+	doc, ops, err := applyPatch(doc, patch)
+	if err != nil {
+		return nil, err
+	}
+	var affected [][]string
+	for _, op := range ops {
+		affected = append(affected, op.affected()...)
+	}
+	if err := s.validateAffected(doc, affected, affectedOnly); err != nil {
+		ve, ok := err.(*ValidationError)
+		if !ok {
+			return nil, err
+		}
+		return nil, &PatchError{responsibleOp(ops, ve.BestMatch().InstanceLocation), ve}
+	}
+	return doc, nil
+}
+
+// responsibleOp returns the index of last operation in ops, which modified
+// the instance location loc, its ancestors or its descendants. returns -1
+// if no operation modified it.
+func responsibleOp(ops []*patchOp, loc string) int {
+	related := func(tokens []string) bool {
+		oploc := instanceLocation(tokens)
+		return oploc == loc || strings.HasPrefix(oploc, loc+"/") || strings.HasPrefix(loc, oploc+"/") || oploc == ""
+	}
+	for i := len(ops) - 1; i >= 0; i-- {
+		switch op := ops[i]; op.op {
+		case "test":
+		case "move":
+			if related(op.from) || related(op.path) {
+				return i
+			}
+		default:
+			if related(op.path) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// ValidateMergePatch applies RFC 7396 JSON Merge Patch to doc, and validates
+// the result against s. doc is not modified. It returns the patched document.
+//
+// affectedOnly has same meaning as in ValidatePatch.
+func (s *Schema) ValidateMergePatch(doc, patch interface{}, affectedOnly bool) (interface{}, error) {
+	var affected [][]string
+	doc = mergePatch(deepCopy(doc), patch, nil, &affected)
+	if err := s.validateAffected(doc, affected, affectedOnly); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// validateAffected validates doc against s. If affectedOnly is true, only
+// the values at given affected locations are validated.
+func (s *Schema) validateAffected(doc interface{}, affected [][]string, affectedOnly bool) (err error) {
+	if !affectedOnly {
+		return s.Validate(doc)
+	}
+	defer recoverValidation(&err)
+
+	// validate only outermost locations, which still exist
+	var roots [][]string
+	for _, tokens := range affected {
+		for len(tokens) > 0 {
+			if _, err := patchGet(doc, tokens); err == nil {
+				break
+			}
+			tokens = tokens[:len(tokens)-1]
+		}
+		outer := true
+		for i := 0; i < len(roots); i++ {
+			if hasPrefix(tokens, roots[i]) {
+				outer = false
+				break
+			}
+			if hasPrefix(roots[i], tokens) {
+				roots = append(roots[:i], roots[i+1:]...)
+				i--
+			}
+		}
+		if outer {
+			roots = append(roots, tokens)
+		}
+	}
+
+	var errs []error
+	for _, tokens := range roots {
+		verrs, err := s.validateAt(doc, tokens)
+		if err != nil {
+			return err
+		}
+		errs = append(errs, verrs...)
+	}
+	if len(errs) > 0 {
+		return s.validationFailed("", errs...)
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package jsonschema_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+func TestApplyPatch(t *testing.T) {
+	tests := []struct {
+		description string
+		doc         string
+		patch       string
+		want        string // empty if patch must fail
+		index       int    // index of failing operation
+	}{
+		{"add property", `{"a":1}`, `[{"op":"add","path":"/b","value":2}]`, `{"a":1,"b":2}`, 0},
+		{"add item", `[1,3]`, `[{"op":"add","path":"/1","value":2}]`, `[1,2,3]`, 0},
+		{"append item", `[1,2]`, `[{"op":"add","path":"/-","value":3}]`, `[1,2,3]`, 0},
+		{"replace root", `{"a":1}`, `[{"op":"add","path":"","value":[1]}]`, `[1]`, 0},
+		{"remove", `{"a":[1,2,3]}`, `[{"op":"remove","path":"/a/1"}]`, `{"a":[1,3]}`, 0},
+		{"replace", `{"a":{"b":1}}`, `[{"op":"replace","path":"/a/b","value":2}]`, `{"a":{"b":2}}`, 0},
+		{"move", `{"a":{"b":1},"c":{}}`, `[{"op":"move","from":"/a/b","path":"/c/d"}]`, `{"a":{},"c":{"d":1}}`, 0},
+		{"copy", `{"a":[1]}`, `[{"op":"copy","from":"/a","path":"/b"}]`, `{"a":[1],"b":[1]}`, 0},
+		{"escaped tokens", `{"a/b":{"c~d":1}}`, `[{"op":"remove","path":"/a~1b/c~0d"}]`, `{"a/b":{}}`, 0},
+		{"test", `{"a":1}`, `[{"op":"test","path":"/a","value":1}]`, `{"a":1}`, 0},
+		{"test fails", `{"a":1}`, `[{"op":"add","path":"/b","value":2},{"op":"test","path":"/a","value":2}]`, ``, 1},
+		{"missing parent", `{}`, `[{"op":"add","path":"/a/b","value":2}]`, ``, 0},
+		{"invalid index", `[1]`, `[{"op":"add","path":"/01","value":2}]`, ``, 0},
+		{"remove missing", `{}`, `[{"op":"test","path":"","value":{}},{"op":"remove","path":"/a"}]`, ``, 1},
+		{"replace missing", `{}`, `[{"op":"replace","path":"/a","value":1}]`, ``, 0},
+		{"move into child", `{"a":{}}`, `[{"op":"move","from":"/a","path":"/a/b"}]`, ``, 0},
+		{"unknown op", `{}`, `[{"op":"delete","path":"/a"}]`, ``, 0},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			doc := decodeString(t, test.doc)
+			got, err := jsonschema.ApplyPatch(doc, decodeString(t, test.patch))
+			if test.want == "" {
+				var pe *jsonschema.PatchError
+				if !errors.As(err, &pe) {
+					t.Fatalf("want *PatchError, got %#v", err)
+				}
+				if pe.Index != test.index {
+					t.Errorf("index: got %d, want %d", pe.Index, test.index)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := decodeString(t, test.want); !reflect.DeepEqual(got, want) {
+				t.Errorf("got %v, want %v", got, want)
+			}
+			if !reflect.DeepEqual(doc, decodeString(t, test.doc)) {
+				t.Error("doc must not be modified")
+			}
+		})
+	}
+}
+
+func TestMergePatch(t *testing.T) {
+	doc := decodeString(t, `{"a":"b","c":{"d":"e","f":"g"},"h":1}`)
+	patch := decodeString(t, `{"a":"z","c":{"f":null},"h":{"i":1}}`)
+	want := decodeString(t, `{"a":"z","c":{"d":"e"},"h":{"i":1}}`)
+	if got := jsonschema.MergePatch(doc, patch); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got := jsonschema.MergePatch(doc, decodeString(t, `[1]`)); !reflect.DeepEqual(got, decodeString(t, `[1]`)) {
+		t.Errorf("got %v, want [1]", got)
+	}
+}
+
+func TestSchema_ValidatePatch(t *testing.T) {
+	sch := jsonschema.MustCompileString("schema.json", `{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string"},
+			"tags": {"type": "array", "items": {"type": "string"}, "maxItems": 2},
+			"owner": {"$ref": "#/$defs/person"}
+		},
+		"additionalProperties": false,
+		"$defs": {
+			"person": {"required": ["email"], "properties": {"email": {"type": "string"}}}
+		}
+	}`)
+
+	// existing doc violates schema at /owner
+	doc := decodeString(t, `{"name":"x","tags":["a"],"owner":{}}`)
+
+	tests := []struct {
+		description  string
+		patch        string
+		affectedOnly bool
+		index        int    // index of responsible operation, -2 if valid
+		location     string // instance location of best match
+	}{
+		{"valid in affected mode", `[{"op":"add","path":"/tags/-","value":"b"}]`, true, -2, ""},
+		{"invalid in full mode", `[{"op":"add","path":"/tags/-","value":"b"}]`, false, -1, "/owner"},
+		{"maxItems", `[{"op":"add","path":"/name","value":"y"},{"op":"add","path":"/tags/-","value":"b"},{"op":"add","path":"/tags/-","value":"c"}]`, true, 2, "/tags"},
+		{"item type", `[{"op":"add","path":"/tags/0","value":1},{"op":"replace","path":"/name","value":"y"}]`, true, 0, "/tags/0"},
+		{"additionalProperties", `[{"op":"add","path":"/owner/email","value":"a"},{"op":"add","path":"/x","value":1}]`, true, 1, ""},
+		{"through $ref", `[{"op":"add","path":"/owner/email","value":1}]`, true, 0, "/owner/email"},
+		{"fixes invalid", `[{"op":"add","path":"/owner/email","value":"a@b.c"}]`, false, -2, ""},
+		{"move", `[{"op":"move","from":"/tags","path":"/owner/email"},{"op":"test","path":"/name","value":"x"}]`, true, 0, "/owner/email"},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			got, err := sch.ValidatePatch(doc, decodeString(t, test.patch), test.affectedOnly)
+			if test.index == -2 {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got == nil {
+					t.Fatal("patched document must be returned")
+				}
+				return
+			}
+			var pe *jsonschema.PatchError
+			if !errors.As(err, &pe) {
+				t.Fatalf("want *PatchError, got %#v", err)
+			}
+			if pe.Index != test.index {
+				t.Errorf("index: got %d, want %d", pe.Index, test.index)
+			}
+			var ve *jsonschema.ValidationError
+			if !errors.As(err, &ve) {
+				t.Fatalf("want *ValidationError, got %#v", pe.Err)
+			}
+			if loc := ve.BestMatch().InstanceLocation; loc != test.location {
+				t.Errorf("location: got %q, want %q", loc, test.location)
+			}
+			if !strings.HasPrefix(err.Error(), "jsonschema: ") {
+				t.Errorf("unexpected error message %q", err)
+			}
+		})
+	}
+}
+
+func TestSchema_ValidateMergePatch(t *testing.T) {
+	sch := jsonschema.MustCompileString("schema.json", `{
+		"properties": {
+			"a": {"type": "object", "properties": {"b": {"type": "integer"}}},
+			"c": {"type": "string"}
+		}
+	}`)
+	doc := decodeString(t, `{"a":{"b":1},"c":1}`)
+	if _, err := sch.ValidateMergePatch(doc, decodeString(t, `{"a":{"b":2}}`), true); err != nil {
+		t.Errorf("affected only: %v", err)
+	}
+	if _, err := sch.ValidateMergePatch(doc, decodeString(t, `{"a":{"b":2}}`), false); err == nil {
+		t.Error("full: want error")
+	}
+	_, err := sch.ValidateMergePatch(doc, decodeString(t, `{"a":{"b":"x"}}`), true)
+	ve, ok := err.(*jsonschema.ValidationError)
+	if !ok {
+		t.Fatalf("want *ValidationError, got %#v", err)
+	}
+	if loc := ve.BestMatch().InstanceLocation; loc != "/a/b" {
+		t.Errorf("location: got %q, want %q", loc, "/a/b")
+	}
+}
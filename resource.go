@@ -19,6 +19,7 @@ type resource struct {
 	draft        *Draft
 	subresources map[string]*resource // key is floc. only applicable for root resource
 	schema       *Schema
+	checked      bool // values checked. see Compiler.CheckValues
 }
 
 func (r *resource) String() string {
@@ -26,14 +27,18 @@ func (r *resource) String() string {
 }
 
 func newResource(url string, r io.Reader) (*resource, error) {
-	if strings.IndexByte(url, '#') != -1 {
-		panic(fmt.Sprintf("BUG: newResource(%q)", url))
-	}
 	doc, err := unmarshal(r)
 	if err != nil {
 		return nil, fmt.Errorf("jsonschema: invalid json %s: %v", url, err)
 	}
-	url, err = toAbs(url)
+	return newResourceDoc(url, doc)
+}
+
+func newResourceDoc(url string, doc interface{}) (*resource, error) {
+	if strings.IndexByte(url, '#') != -1 {
+		panic(fmt.Sprintf("BUG: newResource(%q)", url))
+	}
+	url, err := toAbs(url)
 	if err != nil {
 		return nil, err
 	}
@@ -6,6 +6,7 @@ import (
 	"math/big"
 	"net/url"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"unicode/utf8"
@@ -15,6 +16,8 @@ import (
 type Schema struct {
 	Location string // absolute location
 
+	draft          *Draft     // draft used to compile this schema.
+	translator     Translator // translates validation error messages. nil means English.
 	dynamicAnchors []*Schema
 
 	// type agnostic validations
@@ -27,10 +30,14 @@ type Schema struct {
 	DynamicAnchor   string
 	DynamicRef      *Schema
 	Types           []string      // allowed types.
+	Nullable        bool          // null is allowed in addition to Types. used only in OpenAPI30.
+	TypeSchemas     []*Schema     // schemas allowed as types in union type. used only in draft3.
+	Disallow        []string      // disallowed types. used only in draft3.
+	DisallowSchemas []*Schema     // schemas disallowed as types. used only in draft3.
 	Constant        []interface{} // first element in slice is constant value. note: slice is used to capture nil constant.
 	Enum            []interface{} // allowed values.
-	enumError       string        // error message for enum fail. captured here to avoid constructing error message every time.
 	Not             *Schema
+	Extends         []*Schema // used only in draft3.
 	AllOf           []*Schema
 	AnyOf           []*Schema
 	OneOf           []*Schema
@@ -41,15 +48,18 @@ type Schema struct {
 	// object validations
 	MinProperties         int         // -1 if not specified.
 	MaxProperties         int         // -1 if not specified.
-	Required              []string    // list of required properties.
+	Required              []string    // list of required properties. in draft3, collected from properties.
 	Properties            *OrderedMap // *Schema
 	PropertyNames         *Schema
 	RegexProperties       bool // property names must be valid regex. used only in draft4 as workaround in metaschema.
 	PatternProperties     map[*regexp.Regexp]*Schema
-	AdditionalProperties  interface{}            // nil or bool or *Schema.
-	Dependencies          map[string]interface{} // map value is *Schema or []string.
-	DependentRequired     map[string][]string
-	DependentSchemas      map[string]*Schema
+	patterns              []*regexp.Regexp // keys of PatternProperties, in declaration order.
+	AdditionalProperties  interface{}      // nil or bool or *Schema.
+	Dependencies          *OrderedMap      // *Schema or []string.
+	DependentRequired     *OrderedMap      // []string
+	DependentSchemas      *OrderedMap      // *Schema
+	PropertyDependencies  *OrderedMap      // *OrderedMap of *Schema. used only in DraftNext.
+	Discriminator         *Discriminator   // used only in OpenAPI30 and OpenAPI31.
 	UnevaluatedProperties *Schema
 
 	// array validations
@@ -94,6 +104,22 @@ type Schema struct {
 
 	// user defined extensions
 	Extensions map[string]ExtSchema
+
+	Data                 map[string]string // "$data" references of keywords. used only when Compiler.Data is true.
+	dataExclusiveMinimum bool              // draft4 "exclusiveMinimum" is true, and "minimum" is "$data" reference.
+	dataExclusiveMaximum bool              // draft4 "exclusiveMaximum" is true, and "maximum" is "$data" reference.
+
+	ErrorMessage *ErrorMessage // used only when Compiler.ErrorMessage is true.
+}
+
+// Discriminator captures OpenAPI discriminator object, which names the property
+// whose value tells which of the alternative schemas the instance conforms to.
+type Discriminator struct {
+	PropertyName string
+	Mapping      map[string]string // property value to schema name or reference.
+
+	keyword      string         // oneOf or anyOf, whose alternative is selected.
+	alternatives map[string]int // property value to index of alternative. nil if not applicable.
 }
 
 func (s *Schema) String() string {
@@ -142,38 +168,60 @@ func (s *Schema) Validate(v interface{}) (err error) {
 }
 
 func (s *Schema) validateValue(v interface{}, vloc string) (err error) {
-	defer func() {
-		if r := recover(); r != nil {
-			switch r := r.(type) {
-			case InfiniteLoopError, InvalidJSONTypeError:
-				err = r.(error)
-			default:
-				panic(r)
-			}
-		}
-	}()
-	if _, err := s.validate(nil, 0, "", v, vloc); err != nil {
-		ve := ValidationError{
-			KeywordLocation:         "",
-			AbsoluteKeywordLocation: s.Location,
-			InstanceLocation:        vloc,
-			Message:                 fmt.Sprintf("doesn't validate with %s", s.Location),
-		}
-		return ve.causes(err)
+	defer recoverValidation(&err)
+	if _, err := s.validate(nil, 0, "", v, v, vloc); err != nil {
+		return s.validationFailed(vloc, err)
 	}
 	return nil
 }
 
-// validate validates given value v with this schema.
-func (s *Schema) validate(scope []schemaRef, vscope int, spath string, v interface{}, vloc string) (result validationResult, err error) {
-	validationError := func(keywordPath string, format string, a ...interface{}) *ValidationError {
+// recoverValidation recovers from panics raised by validate, into err.
+func recoverValidation(err *error) {
+	if r := recover(); r != nil {
+		switch r := r.(type) {
+		case InfiniteLoopError, InvalidJSONTypeError:
+			*err = r.(error)
+		default:
+			panic(r)
+		}
+	}
+}
+
+// validationFailed wraps the errors returned by validate, into error
+// reporting that the value at vloc does not validate with s.
+func (s *Schema) validationFailed(vloc string, errs ...error) error {
+	ve := ValidationError{
+		KeywordLocation:         "",
+		AbsoluteKeywordLocation: s.Location,
+		InstanceLocation:        vloc,
+		Message:                 translate(s.translator, "schema", s.Location),
+	}
+	for _, err := range errs {
+		ve.causes(err)
+	}
+	return &ve
+}
+
+// validate validates given value v at location vloc in doc, with this schema.
+func (s *Schema) validate(scope []schemaRef, vscope int, spath string, doc, v interface{}, vloc string) (result validationResult, err error) {
+	newError := func(keywordPath string, msg string) *ValidationError {
+		keyword := keywordPath
+		if i := strings.IndexByte(keyword, '/'); i != -1 {
+			keyword = keyword[:i]
+		}
 		return &ValidationError{
 			KeywordLocation:         keywordLocation(scope, keywordPath),
 			AbsoluteKeywordLocation: joinPtr(s.Location, keywordPath),
 			InstanceLocation:        vloc,
-			Message:                 fmt.Sprintf(format, a...),
+			Message:                 msg,
+			Keyword:                 keyword,
 		}
 	}
+	validationError := func(keywordPath string, kind ErrorKind) *ValidationError {
+		ve := newError(keywordPath, kind.localize(s.translator))
+		ve.Keyword, ve.Kind = kind.Keyword(), kind
+		return ve
+	}
 
 	sref := schemaRef{spath, s, false}
 	if err := checkLoop(scope[len(scope)-vscope:], sref); err != nil {
@@ -182,8 +230,46 @@ func (s *Schema) validate(scope []schemaRef, vscope int, spath string, v interfa
 	scope = append(scope, sref)
 	vscope++
 
+	if s.ErrorMessage != nil {
+		defer func() {
+			if err == nil {
+				return
+			}
+			base := keywordLocation(scope, "")
+			errs := []*ValidationError{err.(*ValidationError)}
+			if errs[0].Message == "" && errs[0].KeywordLocation == base {
+				errs = errs[0].Causes
+			}
+			switch errs = s.ErrorMessage.apply(errs, base, v, s.Required, newError, validationError); len(errs) {
+			case 0:
+				err = nil
+			case 1:
+				err = errs[0]
+			default:
+				ve := newError("", "") // empty message, used just for wrapping
+				ve.Causes = errs
+				err = ve
+			}
+		}()
+	}
+
+	// pnames returns property names of object instance, in deterministic order
+	var pnames func() []string
 	if om, ok := v.(*OrderedMap); ok {
 		v = om.RawValues()
+		pnames = om.Keys
+	} else if obj, ok := v.(map[string]interface{}); ok {
+		var keys []string
+		pnames = func() []string {
+			if keys == nil {
+				keys = make([]string, 0, len(obj))
+				for pname := range obj {
+					keys = append(keys, pname)
+				}
+				sort.Strings(keys)
+			}
+			return keys
+		}
 	}
 
 	// populate result
@@ -205,12 +291,12 @@ func (s *Schema) validate(scope []schemaRef, vscope int, spath string, v interfa
 		if vpath != "" {
 			vloc += "/" + vpath
 		}
-		_, err := sch.validate(scope, 0, schPath, v, vloc)
+		_, err := sch.validate(scope, 0, schPath, doc, v, vloc)
 		return err
 	}
 
 	validateInplace := func(sch *Schema, schPath string) error {
-		vr, err := sch.validate(scope, vscope, schPath, v, vloc)
+		vr, err := sch.validate(scope, vscope, schPath, doc, v, vloc)
 		if err == nil {
 			// update result
 			for pname := range result.unevalProps {
@@ -229,28 +315,57 @@ func (s *Schema) validate(scope []schemaRef, vscope int, spath string, v interfa
 
 	if s.Always != nil {
 		if !*s.Always {
-			return result, validationError("", "not allowed")
+			return result, validationError("", &FalseSchemaError{})
 		}
 		return result, nil
 	}
 
-	if len(s.Types) > 0 {
-		vType := jsonType(v)
-		matched := false
-		for _, t := range s.Types {
-			if vType == t {
-				matched = true
+	if s.Data != nil {
+		sch, kind := s.resolveData(doc, vloc)
+		if kind != nil {
+			return result, validationError(kind.keyword, kind)
+		}
+		s = sch
+	}
+
+	// draft3 schemas in type/disallow/extends are validated using their location relative to s
+	schPath := func(sch *Schema) string {
+		return strings.TrimPrefix(sch.Location, s.Location+"/")
+	}
+
+	if len(s.Types) > 0 || len(s.TypeSchemas) > 0 {
+		matched := matchesType(v, s.Types) || (s.Nullable && v == nil)
+		var causes []error
+		for _, sch := range s.TypeSchemas {
+			if matched {
 				break
-			} else if t == "integer" && vType == "number" {
-				num, _ := new(big.Rat).SetString(fmt.Sprint(v))
-				if num.IsInt() {
-					matched = true
-					break
-				}
+			}
+			if err := validateInplace(sch, schPath(sch)); err == nil {
+				matched = true
+			} else {
+				causes = append(causes, err)
 			}
 		}
 		if !matched {
-			return result, validationError("type", "expected %s, but got %s", strings.Join(s.Types, " or "), vType)
+			types := s.Types
+			if s.Nullable {
+				types = append(types[:len(types):len(types)], "null")
+			}
+			if len(s.TypeSchemas) > 0 {
+				types = append(types[:len(types):len(types)], "schema")
+			}
+			return result, validationError("type", &TypeError{Want: types, Got: jsonType(v)}).add(causes...)
+		}
+	}
+
+	if len(s.Disallow) > 0 || len(s.DisallowSchemas) > 0 {
+		if matchesType(v, s.Disallow) {
+			return result, validationError("disallow", &DisallowError{Got: jsonType(v)})
+		}
+		for _, sch := range s.DisallowSchemas {
+			if err := validateInplace(sch, schPath(sch)); err == nil {
+				return result, validationError(schPath(sch), &DisallowError{Got: jsonType(v)})
+			}
 		}
 	}
 
@@ -258,12 +373,7 @@ func (s *Schema) validate(scope []schemaRef, vscope int, spath string, v interfa
 
 	if len(s.Constant) > 0 {
 		if !equals(v, s.Constant[0]) {
-			switch jsonType(s.Constant[0]) {
-			case "object", "array":
-				errors = append(errors, validationError("const", "const failed"))
-			default:
-				errors = append(errors, validationError("const", "value must be %#v", s.Constant[0]))
-			}
+			errors = append(errors, validationError("const", &ConstError{Want: s.Constant[0], Got: v}))
 		}
 	}
 
@@ -276,35 +386,55 @@ func (s *Schema) validate(scope []schemaRef, vscope int, spath string, v interfa
 			}
 		}
 		if !matched {
-			errors = append(errors, validationError("enum", s.enumError))
+			errors = append(errors, validationError("enum", &EnumError{Want: s.Enum, Got: v}))
 		}
 	}
 
 	if s.format != nil && !s.format(v) {
-		var val = v
-		if v, ok := v.(string); ok {
-			val = quote(v)
+		errors = append(errors, validationError("format", &FormatError{Want: s.Format, Got: v}))
+	}
+
+	// contains + minContains + maxContains
+	checkContains := func(matched int, causes []error) {
+		if s.MinContains != -1 && matched < s.MinContains {
+			errors = append(errors, validationError("minContains", &MinContainsError{Want: s.MinContains, Got: matched}).add(causes...))
+		}
+		if s.MaxContains != -1 && matched > s.MaxContains {
+			errors = append(errors, validationError("maxContains", &MaxContainsError{Want: s.MaxContains, Got: matched}))
 		}
-		errors = append(errors, validationError("format", "%v is not valid %s", val, quote(s.Format)))
 	}
 
 	switch v := v.(type) {
 	case map[string]interface{}:
 		if s.MinProperties != -1 && len(v) < s.MinProperties {
-			errors = append(errors, validationError("minProperties", "minimum %d properties allowed, but found %d properties", s.MinProperties, len(v)))
+			errors = append(errors, validationError("minProperties", &MinPropertiesError{Want: s.MinProperties, Got: len(v)}))
 		}
 		if s.MaxProperties != -1 && len(v) > s.MaxProperties {
-			errors = append(errors, validationError("maxProperties", "maximum %d properties allowed, but found %d properties", s.MaxProperties, len(v)))
+			errors = append(errors, validationError("maxProperties", &MaxPropertiesError{Want: s.MaxProperties, Got: len(v)}))
 		}
-		if len(s.Required) > 0 {
+		if len(s.Required) > 0 && s.draft.version < 4 {
+			for _, pname := range s.Required {
+				if _, ok := v[pname]; !ok {
+					errors = append(errors, validationError("properties/"+escape(pname)+"/required", &RequiredError{Missing: []string{pname}}))
+				}
+			}
+		} else if len(s.Required) > 0 {
 			var missing []string
 			for _, pname := range s.Required {
 				if _, ok := v[pname]; !ok {
-					missing = append(missing, quote(pname))
+					missing = append(missing, pname)
 				}
 			}
 			if len(missing) > 0 {
-				errors = append(errors, validationError("required", "missing properties: %s", strings.Join(missing, ", ")))
+				errors = append(errors, validationError("required", &RequiredError{Missing: missing}))
+			}
+		}
+		if s.Discriminator != nil {
+			pname := s.Discriminator.PropertyName
+			if pvalue, ok := v[pname]; !ok {
+				errors = append(errors, validationError("discriminator", &DiscriminatorError{Property: pname, Missing: true}))
+			} else if _, ok := pvalue.(string); !ok {
+				errors = append(errors, validationError("discriminator", &DiscriminatorError{Property: pname, Got: pvalue}))
 			}
 		}
 
@@ -320,7 +450,7 @@ func (s *Schema) validate(scope []schemaRef, vscope int, spath string, v interfa
 		}
 
 		if s.PropertyNames != nil {
-			for pname := range v {
+			for _, pname := range pnames() {
 				if err := validate(s.PropertyNames, "propertyNames", pname, escape(pname)); err != nil {
 					errors = append(errors, err)
 				}
@@ -328,17 +458,18 @@ func (s *Schema) validate(scope []schemaRef, vscope int, spath string, v interfa
 		}
 
 		if s.RegexProperties {
-			for pname := range v {
+			for _, pname := range pnames() {
 				if !isRegex(pname) {
-					errors = append(errors, validationError("", "patternProperty %s is not valid regex", quote(pname)))
+					errors = append(errors, validationError("", &RegexPropertyError{Property: pname}))
 				}
 			}
 		}
-		for pattern, sch := range s.PatternProperties {
-			for pname, pvalue := range v {
+		for _, pattern := range s.patterns {
+			sch := s.PatternProperties[pattern]
+			for _, pname := range pnames() {
 				if pattern.MatchString(pname) {
 					delete(result.unevalProps, pname)
-					if err := validate(sch, "patternProperties/"+escape(pattern.String()), pvalue, escape(pname)); err != nil {
+					if err := validate(sch, "patternProperties/"+escape(pattern.String()), v[pname], escape(pname)); err != nil {
 						errors = append(errors, err)
 					}
 				}
@@ -347,11 +478,11 @@ func (s *Schema) validate(scope []schemaRef, vscope int, spath string, v interfa
 		if s.AdditionalProperties != nil {
 			if allowed, ok := s.AdditionalProperties.(bool); ok {
 				if !allowed && len(result.unevalProps) > 0 {
-					errors = append(errors, validationError("additionalProperties", "additionalProperties %s not allowed", result.unevalPnames()))
+					errors = append(errors, validationError("additionalProperties", &AdditionalPropertiesError{Properties: result.unevalPnames(pnames())}))
 				}
 			} else {
 				schema := s.AdditionalProperties.(*Schema)
-				for pname := range result.unevalProps {
+				for _, pname := range result.unevalPnames(pnames()) {
 					if pvalue, ok := v[pname]; ok {
 						if err := validate(schema, "additionalProperties", pvalue, escape(pname)); err != nil {
 							errors = append(errors, err)
@@ -361,8 +492,9 @@ func (s *Schema) validate(scope []schemaRef, vscope int, spath string, v interfa
 			}
 			result.unevalProps = nil
 		}
-		for dname, dvalue := range s.Dependencies {
+		for _, dname := range s.Dependencies.Keys() {
 			if _, ok := v[dname]; ok {
+				dvalue, _ := s.Dependencies.Get(dname)
 				switch dvalue := dvalue.(type) {
 				case *Schema:
 					if err := validateInplace(dvalue, "dependencies/"+escape(dname)); err != nil {
@@ -371,41 +503,68 @@ func (s *Schema) validate(scope []schemaRef, vscope int, spath string, v interfa
 				case []string:
 					for i, pname := range dvalue {
 						if _, ok := v[pname]; !ok {
-							errors = append(errors, validationError("dependencies/"+escape(dname)+"/"+strconv.Itoa(i), "property %s is required, if %s property exists", quote(pname), quote(dname)))
+							errors = append(errors, validationError("dependencies/"+escape(dname)+"/"+strconv.Itoa(i), &DependencyError{Property: dname, Missing: pname}))
 						}
 					}
 				}
 			}
 		}
-		for dname, dvalue := range s.DependentRequired {
+		for _, dname := range s.DependentRequired.Keys() {
 			if _, ok := v[dname]; ok {
-				for i, pname := range dvalue {
+				dvalue, _ := s.DependentRequired.Get(dname)
+				for i, pname := range dvalue.([]string) {
 					if _, ok := v[pname]; !ok {
-						errors = append(errors, validationError("dependentRequired/"+escape(dname)+"/"+strconv.Itoa(i), "property %s is required, if %s property exists", quote(pname), quote(dname)))
+						errors = append(errors, validationError("dependentRequired/"+escape(dname)+"/"+strconv.Itoa(i), &DependentRequiredError{Property: dname, Missing: pname}))
 					}
 				}
 			}
 		}
-		for dname, sch := range s.DependentSchemas {
+		for _, dname := range s.DependentSchemas.Keys() {
 			if _, ok := v[dname]; ok {
-				if err := validateInplace(sch, "dependentSchemas/"+escape(dname)); err != nil {
+				sch, _ := s.DependentSchemas.Get(dname)
+				if err := validateInplace(sch.(*Schema), "dependentSchemas/"+escape(dname)); err != nil {
 					errors = append(errors, err)
 				}
 			}
 		}
+		for _, pname := range s.PropertyDependencies.Keys() {
+			if pvalue, ok := v[pname].(string); ok {
+				values, _ := s.PropertyDependencies.Get(pname)
+				if sch, ok := values.(*OrderedMap).Get(pvalue); ok {
+					if err := validateInplace(sch.(*Schema), "propertyDependencies/"+escape(pname)+"/"+escape(pvalue)); err != nil {
+						errors = append(errors, err)
+					}
+				}
+			}
+		}
+
+		// in DraftNext, contains applies to property values
+		if s.Contains != nil && s.draft.version > 2020 && (s.MinContains != -1 || s.MaxContains != -1) {
+			matched := 0
+			var causes []error
+			for _, pname := range pnames() {
+				if err := validate(s.Contains, "contains", v[pname], escape(pname)); err != nil {
+					causes = append(causes, err)
+				} else {
+					matched++
+					delete(result.unevalProps, pname)
+				}
+			}
+			checkContains(matched, causes)
+		}
 
 	case []interface{}:
 		if s.MinItems != -1 && len(v) < s.MinItems {
-			errors = append(errors, validationError("minItems", "minimum %d items required, but found %d items", s.MinItems, len(v)))
+			errors = append(errors, validationError("minItems", &MinItemsError{Want: s.MinItems, Got: len(v)}))
 		}
 		if s.MaxItems != -1 && len(v) > s.MaxItems {
-			errors = append(errors, validationError("maxItems", "maximum %d items required, but found %d items", s.MaxItems, len(v)))
+			errors = append(errors, validationError("maxItems", &MaxItemsError{Want: s.MaxItems, Got: len(v)}))
 		}
 		if s.UniqueItems {
 			for i := 1; i < len(v); i++ {
 				for j := 0; j < i; j++ {
 					if equals(v[i], v[j]) {
-						errors = append(errors, validationError("uniqueItems", "items at index %d and %d are equal", j, i))
+						errors = append(errors, validationError("uniqueItems", &UniqueItemsError{Duplicates: [2]int{j, i}}))
 					}
 				}
 			}
@@ -440,7 +599,7 @@ func (s *Schema) validate(scope []schemaRef, vscope int, spath string, v interfa
 				if additionalItems {
 					result.unevalItems = nil
 				} else if len(v) > len(items) {
-					errors = append(errors, validationError("additionalItems", "only %d items are allowed, but found %d items", len(items), len(v)))
+					errors = append(errors, validationError("additionalItems", &AdditionalItemsError{Want: len(items), Got: len(v)}))
 				}
 			}
 		}
@@ -462,7 +621,6 @@ func (s *Schema) validate(scope []schemaRef, vscope int, spath string, v interfa
 			}
 		}
 
-		// contains + minContains + maxContains
 		if s.Contains != nil && (s.MinContains != -1 || s.MaxContains != -1) {
 			matched := 0
 			var causes []error
@@ -476,12 +634,7 @@ func (s *Schema) validate(scope []schemaRef, vscope int, spath string, v interfa
 					}
 				}
 			}
-			if s.MinContains != -1 && matched < s.MinContains {
-				errors = append(errors, validationError("minContains", "valid must be >= %d, but got %d", s.MinContains, matched).add(causes...))
-			}
-			if s.MaxContains != -1 && matched > s.MaxContains {
-				errors = append(errors, validationError("maxContains", "valid must be <= %d, but got %d", s.MaxContains, matched))
-			}
+			checkContains(matched, causes)
 		}
 
 	case string:
@@ -489,15 +642,15 @@ func (s *Schema) validate(scope []schemaRef, vscope int, spath string, v interfa
 		if s.MinLength != -1 || s.MaxLength != -1 {
 			length := utf8.RuneCount([]byte(v))
 			if s.MinLength != -1 && length < s.MinLength {
-				errors = append(errors, validationError("minLength", "length must be >= %d, but got %d", s.MinLength, length))
+				errors = append(errors, validationError("minLength", &MinLengthError{Want: s.MinLength, Got: length}))
 			}
 			if s.MaxLength != -1 && length > s.MaxLength {
-				errors = append(errors, validationError("maxLength", "length must be <= %d, but got %d", s.MaxLength, length))
+				errors = append(errors, validationError("maxLength", &MaxLengthError{Want: s.MaxLength, Got: length}))
 			}
 		}
 
 		if s.Pattern != nil && !s.Pattern.MatchString(v) {
-			errors = append(errors, validationError("pattern", "does not match pattern %s", quote(s.Pattern.String())))
+			errors = append(errors, validationError("pattern", &PatternError{Want: s.Pattern.String(), Got: v}))
 		}
 
 		// contentEncoding + contentMediaType
@@ -507,7 +660,7 @@ func (s *Schema) validate(scope []schemaRef, vscope int, spath string, v interfa
 			if s.decoder != nil {
 				b, err := s.decoder(v)
 				if err != nil {
-					errors = append(errors, validationError("contentEncoding", "%s is not %s encoded", quote(v), s.ContentEncoding))
+					errors = append(errors, validationError("contentEncoding", &ContentEncodingError{Want: s.ContentEncoding, Got: v}))
 				} else {
 					content, decoded = b, true
 				}
@@ -517,7 +670,7 @@ func (s *Schema) validate(scope []schemaRef, vscope int, spath string, v interfa
 					content = []byte(v)
 				}
 				if err := s.mediaType(content); err != nil {
-					errors = append(errors, validationError("contentMediaType", "value is not of mediatype %s", quote(s.ContentMediaType)))
+					errors = append(errors, validationError("contentMediaType", &ContentMediaTypeError{Want: s.ContentMediaType}))
 				}
 			}
 		}
@@ -531,25 +684,25 @@ func (s *Schema) validate(scope []schemaRef, vscope int, spath string, v interfa
 			}
 			return numVal
 		}
-		f64 := func(r *big.Rat) float64 {
-			f, _ := r.Float64()
-			return f
-		}
 		if s.Minimum != nil && num().Cmp(s.Minimum) < 0 {
-			errors = append(errors, validationError("minimum", "must be >= %v but found %v", f64(s.Minimum), v))
+			errors = append(errors, validationError("minimum", &MinimumError{Want: s.Minimum, Got: v}))
 		}
 		if s.ExclusiveMinimum != nil && num().Cmp(s.ExclusiveMinimum) <= 0 {
-			errors = append(errors, validationError("exclusiveMinimum", "must be > %v but found %v", f64(s.ExclusiveMinimum), v))
+			errors = append(errors, validationError("exclusiveMinimum", &ExclusiveMinimumError{Want: s.ExclusiveMinimum, Got: v}))
 		}
 		if s.Maximum != nil && num().Cmp(s.Maximum) > 0 {
-			errors = append(errors, validationError("maximum", "must be <= %v but found %v", f64(s.Maximum), v))
+			errors = append(errors, validationError("maximum", &MaximumError{Want: s.Maximum, Got: v}))
 		}
 		if s.ExclusiveMaximum != nil && num().Cmp(s.ExclusiveMaximum) >= 0 {
-			errors = append(errors, validationError("exclusiveMaximum", "must be < %v but found %v", f64(s.ExclusiveMaximum), v))
+			errors = append(errors, validationError("exclusiveMaximum", &ExclusiveMaximumError{Want: s.ExclusiveMaximum, Got: v}))
 		}
 		if s.MultipleOf != nil {
 			if q := new(big.Rat).Quo(num(), s.MultipleOf); !q.IsInt() {
-				errors = append(errors, validationError("multipleOf", "%v not multipleOf %v", v, f64(s.MultipleOf)))
+				if s.draft.version < 4 {
+					errors = append(errors, validationError("divisibleBy", &DivisibleByError{Want: s.MultipleOf, Got: v}))
+				} else {
+					errors = append(errors, validationError("multipleOf", &MultipleOfError{Want: s.MultipleOf, Got: v}))
+				}
 			}
 		}
 	}
@@ -562,7 +715,7 @@ func (s *Schema) validate(scope []schemaRef, vscope int, spath string, v interfa
 				if s.url() == sch.url() {
 					url = sch.loc()
 				}
-				return validationError(refPath, "doesn't validate with %s", quote(url)).causes(err)
+				return validationError(refPath, &RefError{URL: url, keyword: refPath}).causes(err)
 			}
 		}
 		return nil
@@ -608,17 +761,50 @@ func (s *Schema) validate(scope []schemaRef, vscope int, spath string, v interfa
 	}
 
 	if s.Not != nil && validateInplace(s.Not, "not") == nil {
-		errors = append(errors, validationError("not", "not failed"))
+		errors = append(errors, validationError("not", &NotError{}))
+	}
+
+	for _, sch := range s.Extends {
+		if err := validateInplace(sch, schPath(sch)); err != nil {
+			errors = append(errors, validationError(schPath(sch), &ExtendsError{}).add(err))
+		}
 	}
 
 	for i, sch := range s.AllOf {
 		schPath := "allOf/" + strconv.Itoa(i)
 		if err := validateInplace(sch, schPath); err != nil {
-			errors = append(errors, validationError(schPath, "allOf failed").add(err))
+			errors = append(errors, validationError(schPath, &AllOfError{}).add(err))
+		}
+	}
+
+	// discriminator selects the alternative to be validated
+	dispatched := ""
+	if d := s.Discriminator; d != nil && d.alternatives != nil {
+		if obj, ok := v.(map[string]interface{}); ok {
+			dispatched = d.keyword
+			// missing or non-string value is reported in object validations
+			if pvalue, ok := obj[d.PropertyName].(string); ok {
+				if i, ok := d.alternatives[pvalue]; ok {
+					alternatives := s.OneOf
+					if d.keyword == "anyOf" {
+						alternatives = s.AnyOf
+					}
+					if err := validateInplace(alternatives[i], d.keyword+"/"+strconv.Itoa(i)); err != nil {
+						errors = append(errors, err)
+					}
+				} else {
+					values := make([]string, 0, len(d.alternatives))
+					for value := range d.alternatives {
+						values = append(values, value)
+					}
+					sort.Strings(values)
+					errors = append(errors, validationError("discriminator", &DiscriminatorError{Property: d.PropertyName, Got: pvalue, Want: values}))
+				}
+			}
 		}
 	}
 
-	if len(s.AnyOf) > 0 {
+	if len(s.AnyOf) > 0 && dispatched != "anyOf" {
 		matched := false
 		var causes []error
 		for i, sch := range s.AnyOf {
@@ -629,11 +815,11 @@ func (s *Schema) validate(scope []schemaRef, vscope int, spath string, v interfa
 			}
 		}
 		if !matched {
-			errors = append(errors, validationError("anyOf", "anyOf failed").add(causes...))
+			errors = append(errors, validationError("anyOf", &AnyOfError{}).add(causes...))
 		}
 	}
 
-	if len(s.OneOf) > 0 {
+	if len(s.OneOf) > 0 && dispatched != "oneOf" {
 		matched := -1
 		var causes []error
 		for i, sch := range s.OneOf {
@@ -641,7 +827,7 @@ func (s *Schema) validate(scope []schemaRef, vscope int, spath string, v interfa
 				if matched == -1 {
 					matched = i
 				} else {
-					errors = append(errors, validationError("oneOf", "valid against schemas at indexes %d and %d", matched, i))
+					errors = append(errors, validationError("oneOf", &OneOfError{Matched: []int{matched, i}}))
 					break
 				}
 			} else {
@@ -649,7 +835,7 @@ func (s *Schema) validate(scope []schemaRef, vscope int, spath string, v interfa
 			}
 		}
 		if matched == -1 {
-			errors = append(errors, validationError("oneOf", "oneOf failed").add(causes...))
+			errors = append(errors, validationError("oneOf", &OneOfError{}).add(causes...))
 		}
 	}
 
@@ -661,13 +847,13 @@ func (s *Schema) validate(scope []schemaRef, vscope int, spath string, v interfa
 		if err == nil {
 			if s.Then != nil {
 				if err := validateInplace(s.Then, "then"); err != nil {
-					errors = append(errors, validationError("then", "if-then failed").add(err))
+					errors = append(errors, validationError("then", &ThenError{}).add(err))
 				}
 			}
 		} else {
 			if s.Else != nil {
 				if err := validateInplace(s.Else, "else"); err != nil {
-					errors = append(errors, validationError("else", "if-else failed").add(err))
+					errors = append(errors, validationError("else", &ElseError{}).add(err))
 				}
 			}
 		}
@@ -675,8 +861,8 @@ func (s *Schema) validate(scope []schemaRef, vscope int, spath string, v interfa
 		scope[len(scope)-1].discard = false
 	}
 
-	for _, ext := range s.Extensions {
-		if err := ext.Validate(ValidationContext{result, validate, validateInplace, validationError}, v); err != nil {
+	for _, name := range sortedKeys(s.Extensions) {
+		if err := s.Extensions[name].Validate(ValidationContext{result, validate, validateInplace, newError}, v); err != nil {
 			errors = append(errors, err)
 		}
 	}
@@ -685,7 +871,7 @@ func (s *Schema) validate(scope []schemaRef, vscope int, spath string, v interfa
 	switch v := v.(type) {
 	case map[string]interface{}:
 		if s.UnevaluatedProperties != nil {
-			for pname := range result.unevalProps {
+			for _, pname := range result.unevalPnames(pnames()) {
 				if pvalue, ok := v[pname]; ok {
 					if err := validate(s.UnevaluatedProperties, "UnevaluatedProperties", pvalue, escape(pname)); err != nil {
 						errors = append(errors, err)
@@ -711,7 +897,7 @@ func (s *Schema) validate(scope []schemaRef, vscope int, spath string, v interfa
 	case 1:
 		return result, errors[0]
 	default:
-		return result, validationError("", "").add(errors...) // empty message, used just for wrapping
+		return result, newError("", "").add(errors...) // empty message, used just for wrapping
 	}
 }
 
@@ -720,12 +906,15 @@ type validationResult struct {
 	unevalItems map[int]struct{}
 }
 
-func (vr validationResult) unevalPnames() string {
-	pnames := make([]string, 0, len(vr.unevalProps))
-	for pname := range vr.unevalProps {
-		pnames = append(pnames, quote(pname))
+// unevalPnames returns unevaluated properties, in the order of given pnames.
+func (vr validationResult) unevalPnames(pnames []string) []string {
+	uneval := make([]string, 0, len(vr.unevalProps))
+	for _, pname := range pnames {
+		if _, ok := vr.unevalProps[pname]; ok {
+			uneval = append(uneval, pname)
+		}
 	}
-	return strings.Join(pnames, ", ")
+	return uneval
 }
 
 // jsonType returns the json type of given value v.
@@ -749,6 +938,23 @@ func jsonType(v interface{}) string {
 	panic(InvalidJSONTypeError(fmt.Sprintf("%T", v)))
 }
 
+// matchesType tells whether v is of any of given json types.
+func matchesType(v interface{}, types []string) bool {
+	vType := jsonType(v)
+	for _, t := range types {
+		switch {
+		case t == vType || t == "any":
+			return true
+		case t == "integer" && vType == "number":
+			num, _ := new(big.Rat).SetString(fmt.Sprint(v))
+			if num.IsInt() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // equals tells if given two json values are equal or not.
 func equals(v1, v2 interface{}) bool {
 	v1Type := jsonType(v1)
@@ -806,3 +1012,13 @@ func escape(token string) string {
 	token = strings.Replace(token, "/", "~1", -1)
 	return url.PathEscape(token)
 }
+
+// unescape converts given json-pointer token to its original form
+func unescape(token string) string {
+	token = strings.Replace(token, "~1", "/", -1)
+	token = strings.Replace(token, "~0", "~", -1)
+	if t, err := url.PathUnescape(token); err == nil {
+		token = t
+	}
+	return token
+}
@@ -24,6 +24,17 @@ import (
 var testSuite = "testdata/JSON-Schema-Test-Suite@3fcee38"
 
 var skipTests = map[string]map[string][]string{
+	"TestDraft3/ref.json": {
+		"$ref prevents a sibling id from changing the base uri": {}, // uses allOf, which is not in draft3
+	},
+	"TestDraft3/optional/zeroTerminatedFloats.json": {
+		"some languages do not distinguish between different types of numeric value": {}, // this behavior is changed in new drafts
+	},
+	"TestDraft3/optional/ecmascript-regex.json": {
+		"ECMA 262 regex dialect recognition": {"[^] is a valid regex"}, // not supported by regexp package
+	},
+	"TestDraft3/optional/format/color.json": {}, // color format is not implemented
+	"TestDraft3/optional/format/time.json":  {}, // draft3 time format does not have timezone
 	"TestDraft4/optional/zeroTerminatedFloats.json": {
 		"some languages do not distinguish between different types of numeric value": {}, // this behavior is changed in new drafts
 	},
@@ -142,6 +153,33 @@ var skipTests = map[string]map[string][]string{
 		"unicode digits are more than 0 through 9":                            {}, // invalid regex "^\\p{digit}+$"
 		"unicode semantics should be used for all patternProperties matching": {}, // invalid regex "\\p{Letter}cole"
 	},
+	"TestDraftNext/optional/format/idn-hostname.json": {}, // idn-hostname format is not implemented
+	"TestDraftNext/optional/format/idn-email.json":    {}, // idn-email format is not implemented
+	"TestDraftNext/optional/ecmascript-regex.json": {
+		"ECMA 262 \\s matches whitespace": {
+			"Line tabulation matches",                       // \s does not match vertical tab
+			"latin-1 non-breaking-space matches",            // \s does not match unicode whitespace
+			"zero-width whitespace matches",                 // \s does not match unicode whitespace
+			"paragraph separator matches (line terminator)", // \s does not match unicode whitespace
+			"EM SPACE matches (Space_Separator)",            // \s does not match unicode whitespace
+		},
+		"ECMA 262 \\S matches everything but whitespace": {
+			"Line tabulation does not match",                       // \S matches unicode whitespace
+			"latin-1 non-breaking-space does not match",            // \S matches unicode whitespace
+			"zero-width whitespace does not match",                 // \S matches unicode whitespace
+			"paragraph separator does not match (line terminator)", // \S matches unicode whitespace
+			"EM SPACE does not match (Space_Separator)",            // \S matches unicode whitespace
+		},
+		"ECMA 262 regex escapes control codes with \\c and upper letter":      {}, // \cX is not supported
+		"ECMA 262 regex escapes control codes with \\c and lower letter":      {}, // \cX is not supported
+		"unicode semantics should be used for all pattern matching":           {}, // invalid regex "\\p{Letter}cole"
+		"unicode digits are more than 0 through 9":                            {}, // invalid regex "^\\p{digit}+$"
+		"unicode semantics should be used for all patternProperties matching": {}, // invalid regex "\\p{Letter}cole"
+	},
+}
+
+func TestDraft3(t *testing.T) {
+	testFolder(t, testSuite+"/tests/draft3", jsonschema.Draft3)
 }
 
 func TestDraft4(t *testing.T) {
@@ -164,6 +202,10 @@ func TestDraft2020(t *testing.T) {
 	testFolder(t, testSuite+"/tests/draft2020-12", jsonschema.Draft2020)
 }
 
+func TestDraftNext(t *testing.T) {
+	testFolder(t, testSuite+"/tests/draft-future", jsonschema.DraftNext)
+}
+
 func TestExtra(t *testing.T) {
 	t.Run("draft7", func(t *testing.T) {
 		testFolder(t, "testdata/tests/draft7", jsonschema.Draft7)
@@ -171,6 +213,12 @@ func TestExtra(t *testing.T) {
 	t.Run("draft2020", func(t *testing.T) {
 		testFolder(t, "testdata/tests/draft2020", jsonschema.Draft2020)
 	})
+	t.Run("draft-next", func(t *testing.T) {
+		testFolder(t, "testdata/tests/draft-next", jsonschema.DraftNext)
+	})
+	t.Run("openapi30", func(t *testing.T) {
+		testFolder(t, "testdata/tests/openapi30", jsonschema.OpenAPI30)
+	})
 }
 
 type testGroup struct {
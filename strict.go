@@ -0,0 +1,162 @@
+package jsonschema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// typeKeywords maps the keywords, which apply only to specific instance
+// types, to those types.
+var typeKeywords = map[string][]string{
+	"minLength":             {"string"},
+	"maxLength":             {"string"},
+	"pattern":               {"string"},
+	"contentEncoding":       {"string"},
+	"contentMediaType":      {"string"},
+	"minimum":               {"number", "integer"},
+	"maximum":               {"number", "integer"},
+	"exclusiveMinimum":      {"number", "integer"},
+	"exclusiveMaximum":      {"number", "integer"},
+	"multipleOf":            {"number", "integer"},
+	"divisibleBy":           {"number", "integer"},
+	"items":                 {"array"},
+	"additionalItems":       {"array"},
+	"prefixItems":           {"array"},
+	"contains":              {"array"},
+	"minContains":           {"array"},
+	"maxContains":           {"array"},
+	"minItems":              {"array"},
+	"maxItems":              {"array"},
+	"uniqueItems":           {"array"},
+	"unevaluatedItems":      {"array"},
+	"properties":            {"object"},
+	"patternProperties":     {"object"},
+	"additionalProperties":  {"object"},
+	"required":              {"object"},
+	"minProperties":         {"object"},
+	"maxProperties":         {"object"},
+	"dependencies":          {"object"},
+	"dependentRequired":     {"object"},
+	"dependentSchemas":      {"object"},
+	"propertyDependencies":  {"object"},
+	"propertyNames":         {"object"},
+	"unevaluatedProperties": {"object"},
+}
+
+// refSiblings lists the keywords, which are allowed along with "$ref"
+// in strict mode, before draft2019.
+var refSiblings = []string{"$schema", "$id", "id", "$comment", "definitions", "title", "description", "default", "examples", "readOnly", "writeOnly"}
+
+// checkStrict reports the problems in schema m, that are ignored
+// silently unless Compiler.Strict is true.
+func (c *Compiler) checkStrict(r *resource, res *resource, m *OrderedMap) error {
+	loc := r.url + res.floc
+	for _, kw := range m.Keys() {
+		if !c.isKeyword(r.draft, kw) {
+			return fmt.Errorf("jsonschema: unknown keyword %s in %s", quote(kw), loc)
+		}
+	}
+
+	if _, ok := m.Get("$ref"); ok && r.draft.version < 2019 {
+		for _, kw := range m.Keys() {
+			if kw != "$ref" && !contains(refSiblings, kw) {
+				return fmt.Errorf("jsonschema: %s is ignored along with $ref in %s", quote(kw), loc)
+			}
+		}
+	}
+
+	if format, ok := m.Get("format"); ok {
+		if format, ok := format.(string); ok {
+			if _, ok := Formats[format]; !ok {
+				return fmt.Errorf("jsonschema: unknown format %s in %s", quote(format), loc)
+			}
+		}
+	}
+
+	var types []string
+	switch t := m.RawValues()["type"].(type) {
+	case string:
+		types = []string{t}
+	case []interface{}:
+		for _, item := range t {
+			if item, ok := item.(string); ok {
+				types = append(types, item)
+			} else {
+				// draft3: schema in union type
+				return nil
+			}
+		}
+	}
+	if len(types) == 0 || contains(types, "any") {
+		return nil
+	}
+	for _, kw := range m.Keys() {
+		want, ok := typeKeywords[kw]
+		if !ok || (kw == "required" && r.draft.version < 4) {
+			continue
+		}
+		compatible := false
+		for _, t := range want {
+			if contains(types, t) || (t == "integer" && contains(types, "number")) {
+				compatible = true
+				break
+			}
+		}
+		if !compatible {
+			return fmt.Errorf("jsonschema: %s is not applicable to type %s in %s", quote(kw), quoteAll(types), loc)
+		}
+	}
+	return nil
+}
+
+// isKeyword tells whether kw is keyword in draft d, or claimed by a
+// registered extension enabled in d. Extensions claim the properties of
+// their meta-schema. OpenAPI allows specification extensions, which are
+// prefixed with "x-".
+func (c *Compiler) isKeyword(d *Draft, kw string) bool {
+	switch kw {
+	case "discriminator":
+		if d.openapi || c.Discriminator {
+			return true
+		}
+	case "errorMessage":
+		if c.ErrorMessage {
+			return true
+		}
+	}
+	if contains(d.keywords, kw) || (d.openapi && strings.HasPrefix(kw, "x-")) {
+		return true
+	}
+	for name, ext := range c.extensions {
+		if d.isExtEnabled(name) && metaKeyword(ext.meta, kw, map[*Schema]bool{}) {
+			return true
+		}
+	}
+	return false
+}
+
+// metaKeyword tells whether kw is declared in "properties" or matches
+// "patternProperties" of meta-schema meta, or of the meta-schemas it
+// refers to through "$ref", "$recursiveRef", "$dynamicRef" and "allOf".
+func metaKeyword(meta *Schema, kw string, visited map[*Schema]bool) bool {
+	if meta == nil || visited[meta] {
+		return false
+	}
+	visited[meta] = true
+	if meta.Properties != nil {
+		if _, ok := meta.Properties.Get(kw); ok {
+			return true
+		}
+	}
+	for _, re := range meta.patterns {
+		if re.MatchString(kw) {
+			return true
+		}
+	}
+	for _, sch := range append([]*Schema{meta.Ref, meta.RecursiveRef, meta.DynamicRef}, meta.AllOf...) {
+		if metaKeyword(sch, kw, visited) {
+			return true
+		}
+	}
+	return false
+}
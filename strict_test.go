@@ -0,0 +1,187 @@
+package jsonschema_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+func TestCompiler_Strict(t *testing.T) {
+	tests := []struct {
+		description string
+		schema      string
+		err         string // substring of error. empty if valid
+	}{
+		{
+			description: "valid schema",
+			schema: `{
+				"$id": "http://example.com/schema.json",
+				"$defs": {"name": {"type": "string", "minLength": 1, "format": "email"}},
+				"type": "object",
+				"properties": {
+					"name": {"$ref": "#/$defs/name", "description": "name"},
+					"age": {"type": ["integer", "null"], "minimum": 0},
+					"tags": {"type": "array", "items": {"type": "string"}, "uniqueItems": true},
+					"any": {"minLength": 1, "minimum": 1}
+				},
+				"required": ["name"]
+			}`,
+		},
+		{
+			description: "unknown keyword",
+			schema:      `{"properties": {"name": {"type": "string", "minLenght": 3}}}`,
+			err:         `unknown keyword 'minLenght' in file://`,
+		},
+		{
+			description: "unknown format",
+			schema:      `{"type": "string", "format": "emial"}`,
+			err:         `unknown format 'emial'`,
+		},
+		{
+			description: "$ref siblings in draft7",
+			schema:      `{"$schema": "http://json-schema.org/draft-07/schema#", "definitions": {"a": {}}, "properties": {"a": {"$ref": "#/definitions/a", "maxLength": 3}}}`,
+			err:         `'maxLength' is ignored along with $ref`,
+		},
+		{
+			description: "$ref siblings in draft2020",
+			schema:      `{"$defs": {"a": {}}, "properties": {"a": {"$ref": "#/$defs/a", "maxLength": 3}}}`,
+		},
+		{
+			description: "type incompatible keyword",
+			schema:      `{"type": "integer", "minLength": 3}`,
+			err:         `'minLength' is not applicable to type 'integer'`,
+		},
+		{
+			description: "integer keyword with number",
+			schema:      `{"type": "integer", "multipleOf": 2}`,
+		},
+		{
+			description: "draft2019 keyword in draft4",
+			schema:      `{"$schema": "http://json-schema.org/draft-04/schema#", "$defs": {}}`,
+			err:         `unknown keyword '$defs'`,
+		},
+		{
+			description: "draft3 required",
+			schema:      `{"$schema": "http://json-schema.org/draft-03/schema#", "type": "string", "required": true}`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			c := jsonschema.NewCompiler()
+			c.Strict = true
+			if err := c.AddResource("schema.json", strings.NewReader(test.schema)); err != nil {
+				t.Fatal(err)
+			}
+			_, err := c.Compile("schema.json")
+			if test.err == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("want error")
+			}
+			if !strings.Contains(err.Error(), test.err) {
+				t.Fatalf("error %q does not contain %q", err, test.err)
+			}
+
+			// without strict mode
+			if _, err := jsonschema.CompileString("schema.json", test.schema); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestCompiler_StrictOptIns(t *testing.T) {
+	c := jsonschema.NewCompiler()
+	c.Strict = true
+	c.ErrorMessage = true
+	c.Discriminator = true
+	c.RegisterExtension("powerOf", jsonschema.MustCompileString("powerOf.json", `{"properties": {"powerOf": {"type": "integer"}}}`), powerOfCompiler{})
+	schema := `{
+		"type": "object",
+		"errorMessage": "invalid pet",
+		"discriminator": {"propertyName": "kind"},
+		"oneOf": [{"properties": {"kind": {"const": "a"}}}],
+		"properties": {"size": {"type": "integer", "powerOf": 2}}
+	}`
+	if err := c.AddResource("schema.json", strings.NewReader(schema)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Compile("schema.json"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCompiler_StrictKeywords(t *testing.T) {
+	drafts := map[string]*jsonschema.Draft{
+		"draft3":    jsonschema.Draft3,
+		"draft4":    jsonschema.Draft4,
+		"draft6":    jsonschema.Draft6,
+		"draft7":    jsonschema.Draft7,
+		"draft2019": jsonschema.Draft2019,
+		"draft2020": jsonschema.Draft2020,
+		"next":      jsonschema.DraftNext,
+		"openapi30": jsonschema.OpenAPI30,
+		"openapi31": jsonschema.OpenAPI31,
+	}
+	// drafts in which keyword is known
+	all := "draft3 draft4 draft6 draft7 draft2019 draft2020 next openapi30 openapi31"
+	tests := []struct {
+		schema string
+		drafts string
+	}{
+		{`{"id": "http://example.com/schema.json"}`, "draft3 draft4"},
+		{`{"$id": "http://example.com/schema.json"}`, "draft6 draft7 draft2019 draft2020 next openapi31"},
+		{`{"properties": {"a": {"$ref": "#"}}}`, all},
+		{`{"definitions": {"a": {}}}`, "draft3 draft4 draft6 draft7 draft2019 draft2020 next openapi31"},
+		{`{"$defs": {"a": {}}}`, "draft2019 draft2020 next openapi31"},
+		{`{"title": "x", "description": "x", "default": 1, "format": "email"}`, all},
+		{`{"type": "string", "enum": ["a"]}`, all},
+		{`{"disallow": "string", "extends": {}, "divisibleBy": 2}`, "draft3"},
+		{`{"properties": {"a": {"required": true}}}`, "draft3"},
+		{`{"properties": {"a": {}}, "additionalProperties": {}, "required": ["a"]}`, "draft4 draft6 draft7 draft2019 draft2020 next openapi30 openapi31"},
+		{`{"patternProperties": {"^a": {}}, "dependencies": {"a": ["b"]}}`, "draft3 draft4 draft6 draft7 draft2019 draft2020 next openapi31"},
+		{`{"items": {}, "minItems": 1, "maxItems": 2, "uniqueItems": true}`, all},
+		{`{"items": [{}], "additionalItems": {}}`, "draft3 draft4 draft6 draft7 draft2019"},
+		{`{"minLength": 1, "maxLength": 2, "pattern": "^a"}`, all},
+		{`{"minimum": 1, "maximum": 2, "exclusiveMinimum": true, "exclusiveMaximum": true}`, "draft3 draft4 openapi30"},
+		{`{"exclusiveMinimum": 1, "exclusiveMaximum": 2}`, "draft6 draft7 draft2019 draft2020 next openapi31"},
+		{`{"not": {}, "allOf": [{}], "anyOf": [{}], "oneOf": [{}], "minProperties": 1, "maxProperties": 2, "multipleOf": 2}`, "draft4 draft6 draft7 draft2019 draft2020 next openapi30 openapi31"},
+		{`{"const": 1, "contains": {}, "propertyNames": {}, "examples": [1]}`, "draft6 draft7 draft2019 draft2020 next openapi31"},
+		{`{"if": {}, "then": {}, "else": {}, "contentEncoding": "base64", "contentMediaType": "application/json", "$comment": "x"}`, "draft7 draft2019 draft2020 next openapi31"},
+		{`{"readOnly": true, "writeOnly": true}`, "draft7 draft2019 draft2020 next openapi30 openapi31"},
+		{`{"deprecated": true}`, "draft2019 draft2020 next openapi30 openapi31"},
+		{`{"$anchor": "a", "dependentRequired": {"a": ["b"]}, "dependentSchemas": {"a": {}}, "unevaluatedProperties": {}, "unevaluatedItems": {}}`, "draft2019 draft2020 next openapi31"},
+		{`{"contains": {}, "minContains": 1, "maxContains": 2, "contentSchema": {}}`, "draft2019 draft2020 next openapi31"},
+		{`{"$recursiveAnchor": true, "properties": {"a": {"$recursiveRef": "#"}}}`, "draft2019"},
+		{`{"$dynamicAnchor": "a", "properties": {"a": {"$dynamicRef": "#a"}}, "prefixItems": [{}]}`, "draft2020 next openapi31"},
+		{`{"propertyDependencies": {"a": {"b": {}}}}`, "next"},
+		{`{"nullable": true}`, "openapi30"},
+		{`{"example": 1, "externalDocs": {}, "xml": {}, "x-a": 1}`, "openapi30 openapi31"},
+	}
+	for _, test := range tests {
+		known := strings.Fields(test.drafts)
+		for name, draft := range drafts {
+			c := jsonschema.NewCompiler()
+			c.Draft = draft
+			c.Strict = true
+			if err := c.AddResource("schema.json", strings.NewReader(test.schema)); err != nil {
+				t.Fatal(err)
+			}
+			_, err := c.Compile("schema.json")
+			want := false
+			for _, d := range known {
+				want = want || d == name
+			}
+			if want && err != nil {
+				t.Errorf("%s %s: %v", name, test.schema, err)
+			} else if !want && err == nil {
+				t.Errorf("%s %s: error expected", name, test.schema)
+			}
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package jsonschema
+
+import (
+	"sort"
+	"strconv"
+)
+
+// checkValues validates the values of "default", "examples", "const" and
+// "enum" keywords in the resources loaded so far, against the schema in
+// which they appear. The schemas which are not yet compiled, such as
+// unreferenced "$defs", are compiled. Once the values of a schema are
+// found valid, it is not checked again.
+func (c *Compiler) checkValues() error {
+	for {
+		// compiling may load more resources
+		var pending []*resource
+		urls := make([]string, 0, len(c.resources))
+		for url := range c.resources {
+			urls = append(urls, url)
+		}
+		sort.Strings(urls)
+		for _, url := range urls {
+			r := c.resources[url]
+			if r.draft == nil {
+				continue
+			}
+			flocs := make([]string, 0, len(r.subresources))
+			for floc := range r.subresources {
+				flocs = append(flocs, floc)
+			}
+			sort.Strings(flocs)
+			for _, res := range append([]*resource{r}, subresources(r, flocs)...) {
+				if res.checked || (res == r && isOpenAPI(r.doc)) {
+					continue
+				}
+				if !hasValues(res.doc) {
+					res.checked = true
+					continue
+				}
+				pending = append(pending, res)
+				if res.schema == nil {
+					if _, err := c.compileRef(r, nil, "IGNORED", r, res.floc); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		if len(pending) == 0 {
+			return nil
+		}
+		for _, res := range pending {
+			if err := c.checkResourceValues(res); err != nil {
+				return err
+			}
+			res.checked = true
+		}
+	}
+}
+
+func subresources(r *resource, flocs []string) []*resource {
+	rr := make([]*resource, len(flocs))
+	for i, floc := range flocs {
+		rr[i] = r.subresources[floc]
+	}
+	return rr
+}
+
+// hasValues tells whether schema sch has any keyword checked
+// by Compiler.CheckValues.
+func hasValues(sch interface{}) bool {
+	if m, ok := sch.(*OrderedMap); ok {
+		for _, kw := range []string{"default", "examples", "example", "const", "enum"} {
+			if _, ok := m.Get(kw); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (c *Compiler) checkResourceValues(res *resource) error {
+	m, ok := res.doc.(*OrderedMap)
+	if !ok {
+		return nil
+	}
+	s := res.schema
+	if _, ok := m.Get("$ref"); ok && s.draft.version < 2019 {
+		// all other properties in a "$ref" object are ignored
+		return nil
+	}
+
+	check := func(loc string, v interface{}) error {
+		if _, ok := s.Data[loc]; ok {
+			// "$data" reference, not value
+			return nil
+		}
+		err := s.Validate(v)
+		if err == nil {
+			return nil
+		}
+		ve, ok := err.(*ValidationError)
+		if !ok {
+			return err
+		}
+		verr := &ValueError{joinPtr(s.Location, loc), ve}
+		if c.Warn != nil {
+			c.Warn(verr)
+			return nil
+		}
+		return verr
+	}
+	checkAll := func(pname string) error {
+		if arr, ok := m.RawValues()[pname].([]interface{}); ok {
+			for i, item := range arr {
+				if err := check(pname+"/"+strconv.Itoa(i), item); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	if v, ok := m.Get("default"); ok {
+		if err := check("default", v); err != nil {
+			return err
+		}
+	}
+	if v, ok := m.Get("example"); ok && s.draft.openapi {
+		if err := check("example", v); err != nil {
+			return err
+		}
+	}
+	if s.draft.version >= 6 {
+		if err := checkAll("examples"); err != nil {
+			return err
+		}
+		if v, ok := m.Get("const"); ok {
+			if err := check("const", v); err != nil {
+				return err
+			}
+		}
+	}
+	return checkAll("enum")
+}
@@ -0,0 +1,121 @@
+package jsonschema_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+func TestCompiler_CheckValues(t *testing.T) {
+	tests := []struct {
+		description string
+		schema      string
+		invalid     []string // value locations of violations
+	}{
+		{
+			description: "valid values",
+			schema: `{
+				"type": "object",
+				"default": {},
+				"properties": {
+					"age": {"type": "integer", "minimum": 0, "default": 0, "examples": [1, 42]},
+					"color": {"type": "string", "enum": ["red", "green"]},
+					"kind": {"type": "string", "const": "x"}
+				}
+			}`,
+		},
+		{
+			description: "invalid default",
+			schema:      `{"properties": {"age": {"type": "integer", "minimum": 0, "default": -1}}}`,
+			invalid:     []string{"schema.json#/properties/age/default"},
+		},
+		{
+			description: "invalid examples",
+			schema:      `{"$defs": {"name": {"type": "string", "minLength": 1, "examples": ["a", "", 1]}}}`,
+			invalid:     []string{"schema.json#/$defs/name/examples/1", "schema.json#/$defs/name/examples/2"},
+		},
+		{
+			description: "invalid const and enum",
+			schema:      `{"items": {"type": "string", "enum": ["a", 1, null]}, "contains": {"type": "integer", "const": "x"}}`,
+			invalid:     []string{"schema.json#/contains/const", "schema.json#/items/enum/1", "schema.json#/items/enum/2"},
+		},
+		{
+			description: "$ref siblings ignored in draft7",
+			schema:      `{"$schema": "http://json-schema.org/draft-07/schema#", "$ref": "#/definitions/int", "default": "x", "definitions": {"int": {"type": "integer"}}}`,
+		},
+		{
+			description: "$ref siblings in draft2020",
+			schema:      `{"$ref": "#/$defs/int", "default": "x", "$defs": {"int": {"type": "integer"}}}`,
+			invalid:     []string{"schema.json#/default"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			// with warnings
+			var got []string
+			c := jsonschema.NewCompiler()
+			c.CheckValues = true
+			c.Warn = func(err error) {
+				var verr *jsonschema.ValueError
+				if !errors.As(err, &verr) {
+					t.Fatalf("want *ValueError, got %#v", err)
+				}
+				got = append(got, verr.ValueLocation[strings.LastIndex(verr.ValueLocation, "/schema.json#")+1:])
+			}
+			if err := c.AddResource("schema.json", strings.NewReader(test.schema)); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := c.Compile("schema.json"); err != nil {
+				t.Fatal(err)
+			}
+			if strings.Join(got, "\n") != strings.Join(test.invalid, "\n") {
+				t.Errorf("got:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(test.invalid, "\n"))
+			}
+
+			// with errors
+			c = jsonschema.NewCompiler()
+			c.CheckValues = true
+			if err := c.AddResource("schema.json", strings.NewReader(test.schema)); err != nil {
+				t.Fatal(err)
+			}
+			_, err := c.Compile("schema.json")
+			if len(test.invalid) == 0 {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			var verr *jsonschema.ValueError
+			if !errors.As(err, &verr) {
+				t.Fatalf("want *ValueError, got %#v", err)
+			}
+			if !strings.HasSuffix(verr.ValueLocation, test.invalid[0]) {
+				t.Errorf("got %s, want %s", verr.ValueLocation, test.invalid[0])
+			}
+			if !strings.Contains(err.Error(), "does not validate with file://") {
+				t.Errorf("unexpected error message %q", err)
+			}
+		})
+	}
+}
+
+func TestCompiler_CheckValuesDisabled(t *testing.T) {
+	if _, err := jsonschema.CompileString("schema.json", `{"type": "integer", "default": "x"}`); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCompiler_CheckValuesCompileTwice(t *testing.T) {
+	c := jsonschema.NewCompiler()
+	c.CheckValues = true
+	if err := c.AddResource("schema.json", strings.NewReader(`{"type": "integer", "default": "x"}`)); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := c.Compile("schema.json"); err == nil {
+			t.Fatalf("compile #%d: error expected", i+1)
+		}
+	}
+}